@@ -0,0 +1,117 @@
+package analyze
+
+import (
+	"fmt"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/samber/lo"
+	"strings"
+)
+
+// Coverage is how a column is covered by the indexes of its table
+type Coverage int
+
+const (
+	NotCovered Coverage = iota
+	// NonLeading columns are only part of indexes after their first part, which serves lookups by the column poorly
+	NonLeading
+	Leading
+)
+
+func (c Coverage) String() string {
+	switch c {
+	case Leading:
+		return "leading"
+	case NonLeading:
+		return "non-leading"
+	default:
+		return "none"
+	}
+}
+
+// filteredSuffixes and filteredNames are the columns commonly filtered by, judging by their names alone
+var (
+	filteredSuffixes = []string{"_id", "_at", "_on", "_date", "_status", "_type"}
+	filteredNames    = []string{"status", "state", "type", "kind", "email", "slug", "code", "external_id", "deleted"}
+)
+
+// Indexes of the table, including its primary key, which is unique even when its source does not say so
+func Indexes(t inspect.Table) []inspect.Index {
+	if t.PrimaryKey == nil {
+		return t.Indexes
+	}
+	pk := *t.PrimaryKey
+	pk.Name = lo.Ternary(pk.Name == "", "PRIMARY KEY", pk.Name)
+	pk.Unique = true
+	return append([]inspect.Index{pk}, t.Indexes...)
+}
+
+// IndexCoverage is the coverage of each column of the table, by column name. Expression parts cover no column
+func IndexCoverage(t inspect.Table) map[string]Coverage {
+	coverage := lo.SliceToMap(t.Columns, func(col inspect.Column) (string, Coverage) { return col.Name, NotCovered })
+	for _, idx := range Indexes(t) {
+		for i, part := range idx.Parts {
+			if part.Column == "" {
+				continue
+			}
+			coverage[part.Column] = max(coverage[part.Column], lo.Ternary(i == 0, Leading, NonLeading))
+		}
+	}
+	return coverage
+}
+
+// MissingIndex is a lookup the indexes of a table do not serve
+type MissingIndex struct {
+	Table   string
+	Columns []string
+	Reason  string
+	// Coverage is of the first of the columns
+	Coverage Coverage
+}
+
+// MissingIndexes lists the foreign keys, and the commonly filtered columns, of the table that no index leads with
+func MissingIndexes(t inspect.Table) []MissingIndex {
+	indexes := Indexes(t)
+	coverage := IndexCoverage(t)
+	var missing []MissingIndex
+	fkColumns := make(map[string]bool)
+	for _, fk := range t.ForeignKeys {
+		for _, col := range fk.Columns {
+			fkColumns[col] = true
+		}
+		if len(fk.Columns) == 0 || lo.SomeBy(indexes, func(idx inspect.Index) bool { return leadsWith(idx, fk.Columns) }) {
+			continue
+		}
+		missing = append(missing, MissingIndex{
+			Table:    t.Name,
+			Columns:  fk.Columns,
+			Reason:   fmt.Sprintf("foreign key %s", fk.Name),
+			Coverage: coverage[fk.Columns[0]],
+		})
+	}
+	for _, col := range t.Columns {
+		if fkColumns[col.Name] || coverage[col.Name] == Leading || !commonlyFiltered(col.Name) {
+			continue
+		}
+		missing = append(missing, MissingIndex{
+			Table:    t.Name,
+			Columns:  []string{col.Name},
+			Reason:   "commonly filtered",
+			Coverage: coverage[col.Name],
+		})
+	}
+	return missing
+}
+
+// leadsWith is true when the first parts of the index are the given columns, in any order
+func leadsWith(idx inspect.Index, columns []string) bool {
+	if len(idx.Parts) < len(columns) {
+		return false
+	}
+	leading := lo.Map(idx.Parts[:len(columns)], func(part inspect.IndexPart, _ int) string { return part.Column })
+	return len(lo.Without(columns, leading...)) == 0
+}
+
+func commonlyFiltered(column string) bool {
+	name := strings.ToLower(column)
+	return lo.Contains(filteredNames, name) || lo.SomeBy(filteredSuffixes, func(suffix string) bool { return strings.HasSuffix(name, suffix) })
+}
@@ -0,0 +1,134 @@
+package analyze
+
+import (
+	"github.com/reallyliri/atlastui/inspect"
+	"reflect"
+	"testing"
+)
+
+func columns(names ...string) []inspect.Column {
+	cols := make([]inspect.Column, len(names))
+	for i, name := range names {
+		cols[i] = inspect.Column{Name: name, Type: "int"}
+	}
+	return cols
+}
+
+func TestIndexCoverage(t *testing.T) {
+	tests := []struct {
+		name  string
+		table inspect.Table
+		want  map[string]Coverage
+	}{
+		{
+			name:  "no indexes",
+			table: inspect.Table{Columns: columns("a", "b")},
+			want:  map[string]Coverage{"a": NotCovered, "b": NotCovered},
+		},
+		{
+			name: "primary key",
+			table: inspect.Table{
+				Columns:    columns("a", "b", "c"),
+				PrimaryKey: &inspect.Index{Parts: []inspect.IndexPart{{Column: "a"}, {Column: "b"}}},
+			},
+			want: map[string]Coverage{"a": Leading, "b": NonLeading, "c": NotCovered},
+		},
+		{
+			name: "leading in any index",
+			table: inspect.Table{
+				Columns: columns("a", "b"),
+				Indexes: []inspect.Index{columnsIndex("ab", false, "a", "b"), columnsIndex("b", false, "b")},
+			},
+			want: map[string]Coverage{"a": Leading, "b": Leading},
+		},
+		{
+			name: "expressions",
+			table: inspect.Table{
+				Columns: columns("email", "name"),
+				Indexes: []inspect.Index{{Name: "lower", Parts: []inspect.IndexPart{{Expr: "lower(email)"}, {Column: "name"}}}},
+			},
+			want: map[string]Coverage{"email": NotCovered, "name": NonLeading},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IndexCoverage(tt.table); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IndexCoverage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingIndexes(t *testing.T) {
+	fk := func(name string, cols ...string) inspect.ForeignKey {
+		return inspect.ForeignKey{Name: name, Columns: cols, References: inspect.ForeignKeyReferences{Table: "other"}}
+	}
+	tests := []struct {
+		name  string
+		table inspect.Table
+		want  []MissingIndex
+	}{
+		{
+			// user_id is also commonly filtered, it is reported once
+			name: "foreign key without index",
+			table: inspect.Table{
+				Name:        "orders",
+				Columns:     columns("id", "user_id"),
+				PrimaryKey:  &inspect.Index{Parts: []inspect.IndexPart{{Column: "id"}}},
+				ForeignKeys: []inspect.ForeignKey{fk("orders_user", "user_id")},
+			},
+			want: []MissingIndex{{Table: "orders", Columns: []string{"user_id"}, Reason: "foreign key orders_user", Coverage: NotCovered}},
+		},
+		{
+			name: "foreign key behind another column",
+			table: inspect.Table{
+				Name:        "orders",
+				Columns:     columns("shop_id", "user_id"),
+				Indexes:     []inspect.Index{columnsIndex("shop_user", false, "shop_id", "user_id")},
+				ForeignKeys: []inspect.ForeignKey{fk("orders_user", "user_id")},
+			},
+			want: []MissingIndex{
+				{Table: "orders", Columns: []string{"user_id"}, Reason: "foreign key orders_user", Coverage: NonLeading},
+			},
+		},
+		{
+			name: "composite foreign key led in another order",
+			table: inspect.Table{
+				Name:        "items",
+				Columns:     columns("order_id", "line", "note"),
+				PrimaryKey:  &inspect.Index{Parts: []inspect.IndexPart{{Column: "line"}, {Column: "order_id"}, {Column: "note"}}},
+				ForeignKeys: []inspect.ForeignKey{fk("items_order", "order_id", "line")},
+			},
+		},
+		{
+			name: "composite foreign key partially led",
+			table: inspect.Table{
+				Name:        "items",
+				Columns:     columns("order_id", "line"),
+				Indexes:     []inspect.Index{columnsIndex("order", false, "order_id")},
+				ForeignKeys: []inspect.ForeignKey{fk("items_order", "order_id", "line")},
+			},
+			want: []MissingIndex{{Table: "items", Columns: []string{"order_id", "line"}, Reason: "foreign key items_order", Coverage: Leading}},
+		},
+		{
+			name: "commonly filtered columns",
+			table: inspect.Table{
+				Name:    "users",
+				Columns: columns("id", "Email", "created_at", "status", "name", "org_id"),
+				Indexes: []inspect.Index{columnsIndex("org_created", false, "org_id", "created_at")},
+			},
+			want: []MissingIndex{
+				{Table: "users", Columns: []string{"Email"}, Reason: "commonly filtered", Coverage: NotCovered},
+				{Table: "users", Columns: []string{"created_at"}, Reason: "commonly filtered", Coverage: NonLeading},
+				{Table: "users", Columns: []string{"status"}, Reason: "commonly filtered", Coverage: NotCovered},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MissingIndexes(tt.table); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingIndexes()\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package analyze
+
+import (
+	"cmp"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/samber/lo"
+	"slices"
+)
+
+// Graph is the analysis of the foreign keys between the tables of a schema.
+// References to tables of other schemas are not part of the graph.
+type Graph struct {
+	Schema string `json:"schema"`
+	// Isolated are the tables neither referencing nor referenced by another table
+	Isolated []string `json:"isolated"`
+	// Cycles are the groups of tables referencing each other, including tables referencing themselves
+	Cycles [][]string `json:"cycles"`
+	// LoadOrder puts the referenced tables before the tables referencing them, reversed it is a truncation order.
+	// The tables of a cycle are adjacent, in their order within the schema.
+	LoadOrder []string `json:"load_order"`
+	// Hubs are the tables referenced by other tables, the most referenced first
+	Hubs []Hub `json:"hubs"`
+}
+
+// Hub is a table along with the number of foreign keys of other tables referencing it
+type Hub struct {
+	Table      string `json:"table"`
+	References int    `json:"references"`
+}
+
+// SchemaGraph analyzes the foreign keys of the schema
+func SchemaGraph(schema inspect.Schema) Graph {
+	g := Graph{Schema: schema.Name, Isolated: []string{}, Cycles: [][]string{}, LoadOrder: []string{}, Hubs: []Hub{}}
+	names := lo.Map(schema.Tables, func(t inspect.Table, _ int) string { return t.Name })
+	order := make(map[string]int, len(names))
+	for i, name := range names {
+		order[name] = i
+	}
+	references := make(map[string][]string)
+	inbound := make(map[string]int)
+	for _, t := range schema.Tables {
+		for _, fk := range t.ForeignKeys {
+			if _, ok := order[fk.References.Table]; !ok {
+				continue
+			}
+			references[t.Name] = append(references[t.Name], fk.References.Table)
+			// a table referencing itself is a cycle, not a hub
+			if fk.References.Table != t.Name {
+				inbound[fk.References.Table]++
+			}
+		}
+	}
+
+	for _, name := range names {
+		if len(references[name]) == 0 && inbound[name] == 0 {
+			g.Isolated = append(g.Isolated, name)
+		}
+		if inbound[name] > 0 {
+			g.Hubs = append(g.Hubs, Hub{Table: name, References: inbound[name]})
+		}
+	}
+	slices.SortStableFunc(g.Hubs, func(a, b Hub) int { return cmp.Compare(b.References, a.References) })
+
+	for _, component := range stronglyConnected(names, references) {
+		slices.SortFunc(component, func(a, b string) int { return cmp.Compare(order[a], order[b]) })
+		if len(component) > 1 || lo.Contains(references[component[0]], component[0]) {
+			g.Cycles = append(g.Cycles, component)
+		}
+		g.LoadOrder = append(g.LoadOrder, component...)
+	}
+	return g
+}
+
+// stronglyConnected finds the groups of tables reachable from each other by Tarjan's algorithm.
+// A group is found only after the groups it references, which makes the order of the groups a load order.
+func stronglyConnected(names []string, references map[string][]string) [][]string {
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	var visit func(name string)
+	visit = func(name string) {
+		index[name] = len(index)
+		lowLink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+		for _, ref := range references[name] {
+			if _, visited := index[ref]; !visited {
+				visit(ref)
+				lowLink[name] = min(lowLink[name], lowLink[ref])
+			} else if onStack[ref] {
+				lowLink[name] = min(lowLink[name], index[ref])
+			}
+		}
+		if lowLink[name] != index[name] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == name {
+				break
+			}
+		}
+		components = append(components, component)
+	}
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			visit(name)
+		}
+	}
+	return components
+}
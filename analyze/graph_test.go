@@ -0,0 +1,147 @@
+package analyze
+
+import (
+	"github.com/reallyliri/atlastui/inspect"
+	"reflect"
+	"testing"
+)
+
+// referencing is a table with a foreign key to each of the referenced tables
+func referencing(name string, referenced ...string) inspect.Table {
+	t := inspect.Table{Name: name}
+	for _, ref := range referenced {
+		t.ForeignKeys = append(t.ForeignKeys, inspect.ForeignKey{
+			Name:       name + "_" + ref,
+			References: inspect.ForeignKeyReferences{Table: ref},
+		})
+	}
+	return t
+}
+
+func TestSchemaGraph(t *testing.T) {
+	tests := []struct {
+		name   string
+		tables []inspect.Table
+		want   Graph
+	}{
+		{
+			name: "empty",
+			want: Graph{Isolated: []string{}, Cycles: [][]string{}, LoadOrder: []string{}, Hubs: []Hub{}},
+		},
+		{
+			name:   "chain",
+			tables: []inspect.Table{referencing("items", "orders"), referencing("orders", "users"), referencing("users"), referencing("logs")},
+			want: Graph{
+				Isolated:  []string{"logs"},
+				Cycles:    [][]string{},
+				LoadOrder: []string{"users", "orders", "items", "logs"},
+				Hubs:      []Hub{{Table: "orders", References: 1}, {Table: "users", References: 1}},
+			},
+		},
+		{
+			name:   "self reference",
+			tables: []inspect.Table{referencing("employees", "employees", "departments"), referencing("departments")},
+			want: Graph{
+				Isolated:  []string{},
+				Cycles:    [][]string{{"employees"}},
+				LoadOrder: []string{"departments", "employees"},
+				Hubs:      []Hub{{Table: "departments", References: 1}},
+			},
+		},
+		{
+			name:   "self reference only",
+			tables: []inspect.Table{referencing("categories", "categories")},
+			want: Graph{
+				Isolated:  []string{},
+				Cycles:    [][]string{{"categories"}},
+				LoadOrder: []string{"categories"},
+				Hubs:      []Hub{},
+			},
+		},
+		{
+			name: "cycle",
+			tables: []inspect.Table{
+				referencing("a", "b"),
+				referencing("b", "c"),
+				referencing("c", "a", "d"),
+				referencing("d"),
+				referencing("e", "a", "b"),
+			},
+			want: Graph{
+				Isolated:  []string{},
+				Cycles:    [][]string{{"a", "b", "c"}},
+				LoadOrder: []string{"d", "a", "b", "c", "e"},
+				Hubs:      []Hub{{Table: "a", References: 2}, {Table: "b", References: 2}, {Table: "c", References: 1}, {Table: "d", References: 1}},
+			},
+		},
+		{
+			name:   "separate cycles",
+			tables: []inspect.Table{referencing("a", "b"), referencing("b", "a"), referencing("c", "d"), referencing("d", "c", "d")},
+			want: Graph{
+				Isolated:  []string{},
+				Cycles:    [][]string{{"a", "b"}, {"c", "d"}},
+				LoadOrder: []string{"a", "b", "c", "d"},
+				Hubs:      []Hub{{Table: "a", References: 1}, {Table: "b", References: 1}, {Table: "c", References: 1}, {Table: "d", References: 1}},
+			},
+		},
+		{
+			// accounts is a table of another schema
+			name:   "other schemas",
+			tables: []inspect.Table{referencing("orders", "accounts"), referencing("users")},
+			want: Graph{
+				Isolated:  []string{"orders", "users"},
+				Cycles:    [][]string{},
+				LoadOrder: []string{"orders", "users"},
+				Hubs:      []Hub{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Schema = "public"
+			if got := SchemaGraph(inspect.Schema{Name: "public", Tables: tt.tables}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SchemaGraph()\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStronglyConnected(t *testing.T) {
+	tests := []struct {
+		name       string
+		names      []string
+		references map[string][]string
+		want       [][]string
+	}{
+		{
+			name:  "no references",
+			names: []string{"a", "b"},
+			want:  [][]string{{"a"}, {"b"}},
+		},
+		{
+			name:       "referenced first",
+			names:      []string{"a", "b", "c"},
+			references: map[string][]string{"a": {"b"}, "b": {"c"}},
+			want:       [][]string{{"c"}, {"b"}, {"a"}},
+		},
+		{
+			name:       "self reference",
+			names:      []string{"a"},
+			references: map[string][]string{"a": {"a"}},
+			want:       [][]string{{"a"}},
+		},
+		{
+			name:       "cycle",
+			names:      []string{"a", "b", "c"},
+			references: map[string][]string{"a": {"b"}, "b": {"a", "c"}},
+			want:       [][]string{{"c"}, {"b", "a"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stronglyConnected(tt.names, tt.references); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stronglyConnected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package analyze
+
+import (
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/samber/lo"
+)
+
+// ImpactKind is how an object depends on a column
+type ImpactKind int
+
+const (
+	// IndexImpact is an index containing the column, including the primary key
+	IndexImpact ImpactKind = iota
+	// ForeignKeyImpact is a foreign key of the column's own table, referencing another table by the column
+	ForeignKeyImpact
+	// ReferenceImpact is a foreign key of another table referencing the column
+	ReferenceImpact
+)
+
+// Impact is an object that breaks when a column is renamed or dropped, along with what breaks in turn
+type Impact struct {
+	Kind ImpactKind
+	// Table is of the index or foreign key
+	Table string
+	Name  string
+	// Column is the column of Table taking part, which is the referencing column of references
+	Column string
+	// Part is the position of the column within the index or foreign key, starting at 1
+	Part  int
+	Parts int
+	// Cycle is set on references looping back to a column leading to them, which are not expanded again
+	Cycle    bool
+	Children []Impact
+}
+
+// ColumnImpact lists what depends on the column: the indexes containing it, the foreign keys of its table using it,
+// and the foreign keys of other tables referencing it. References are followed transitively, along with the indexes
+// of the referencing columns. Tables of other schemas are not followed.
+// Views and functions referencing the column are not listed, as the inspected schema only holds tables.
+func ColumnImpact(schema inspect.Schema, table, column string) []Impact {
+	tables := lo.KeyBy(schema.Tables, func(t inspect.Table) string { return t.Name })
+	t, ok := tables[table]
+	if !ok {
+		return nil
+	}
+	var impacts []Impact
+	for _, fk := range t.ForeignKeys {
+		if pos := lo.IndexOf(fk.Columns, column); pos >= 0 {
+			impacts = append(impacts, Impact{Kind: ForeignKeyImpact, Table: table, Name: fk.Name, Column: column, Part: pos + 1, Parts: len(fk.Columns)})
+		}
+	}
+	path := map[[2]string]bool{{table, column}: true}
+	return append(columnDependents(schema, tables, table, column, path), impacts...)
+}
+
+// columnDependents are the indexes containing the column, and the references to it expanded to their own dependents.
+// The path holds the columns leading to this one, so references looping back to them are not expanded
+func columnDependents(schema inspect.Schema, tables map[string]inspect.Table, table, column string, path map[[2]string]bool) []Impact {
+	var impacts []Impact
+	for _, idx := range Indexes(tables[table]) {
+		if pos := lo.IndexOf(lo.Map(idx.Parts, func(part inspect.IndexPart, _ int) string { return part.Column }), column); pos >= 0 {
+			impacts = append(impacts, Impact{Kind: IndexImpact, Table: table, Name: idx.Name, Column: column, Part: pos + 1, Parts: len(idx.Parts)})
+		}
+	}
+	for _, other := range schema.Tables {
+		for _, fk := range other.ForeignKeys {
+			pos := lo.IndexOf(fk.References.Columns, column)
+			if fk.References.Table != table || pos < 0 || pos >= len(fk.Columns) {
+				continue
+			}
+			ref := Impact{Kind: ReferenceImpact, Table: other.Name, Name: fk.Name, Column: fk.Columns[pos], Part: pos + 1, Parts: len(fk.Columns)}
+			key := [2]string{other.Name, ref.Column}
+			if path[key] {
+				ref.Cycle = true
+			} else {
+				path[key] = true
+				ref.Children = columnDependents(schema, tables, other.Name, ref.Column, path)
+				delete(path, key)
+			}
+			impacts = append(impacts, ref)
+		}
+	}
+	return impacts
+}
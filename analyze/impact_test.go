@@ -0,0 +1,110 @@
+package analyze
+
+import (
+	"github.com/reallyliri/atlastui/inspect"
+	"reflect"
+	"testing"
+)
+
+func foreignKey(name string, columns []string, table string, referenced ...string) inspect.ForeignKey {
+	return inspect.ForeignKey{Name: name, Columns: columns, References: inspect.ForeignKeyReferences{Table: table, Columns: referenced}}
+}
+
+func TestColumnImpact(t *testing.T) {
+	idPrimaryKey := &inspect.Index{Parts: []inspect.IndexPart{{Column: "id"}}}
+	tests := []struct {
+		name   string
+		tables []inspect.Table
+		table  string
+		column string
+		want   []Impact
+	}{
+		{
+			name:   "primary key only",
+			tables: []inspect.Table{{Name: "users", PrimaryKey: idPrimaryKey}},
+			table:  "users",
+			column: "id",
+			want:   []Impact{{Kind: IndexImpact, Table: "users", Name: "PRIMARY KEY", Column: "id", Part: 1, Parts: 1}},
+		},
+		{
+			name:   "unknown table",
+			tables: []inspect.Table{{Name: "users", PrimaryKey: idPrimaryKey}},
+			table:  "accounts",
+			column: "id",
+		},
+		{
+			name: "transitive references",
+			tables: []inspect.Table{
+				{Name: "accounts", PrimaryKey: idPrimaryKey},
+				{
+					Name:        "users",
+					Indexes:     []inspect.Index{columnsIndex("users_account_key", true, "account_id")},
+					ForeignKeys: []inspect.ForeignKey{foreignKey("users_account", []string{"account_id"}, "accounts", "id")},
+				},
+				{
+					Name:        "profiles",
+					ForeignKeys: []inspect.ForeignKey{foreignKey("profiles_user", []string{"user_id"}, "users", "account_id")},
+				},
+			},
+			table:  "accounts",
+			column: "id",
+			want: []Impact{
+				{Kind: IndexImpact, Table: "accounts", Name: "PRIMARY KEY", Column: "id", Part: 1, Parts: 1},
+				{Kind: ReferenceImpact, Table: "users", Name: "users_account", Column: "account_id", Part: 1, Parts: 1, Children: []Impact{
+					{Kind: IndexImpact, Table: "users", Name: "users_account_key", Column: "account_id", Part: 1, Parts: 1},
+					{Kind: ReferenceImpact, Table: "profiles", Name: "profiles_user", Column: "user_id", Part: 1, Parts: 1},
+				}},
+			},
+		},
+		{
+			name: "cycle",
+			tables: []inspect.Table{
+				{Name: "a", ForeignKeys: []inspect.ForeignKey{foreignKey("a_b", []string{"id"}, "b", "id")}},
+				{Name: "b", ForeignKeys: []inspect.ForeignKey{foreignKey("b_a", []string{"id"}, "a", "id")}},
+			},
+			table:  "a",
+			column: "id",
+			want: []Impact{
+				{Kind: ReferenceImpact, Table: "b", Name: "b_a", Column: "id", Part: 1, Parts: 1, Children: []Impact{
+					{Kind: ReferenceImpact, Table: "a", Name: "a_b", Column: "id", Part: 1, Parts: 1, Cycle: true},
+				}},
+				{Kind: ForeignKeyImpact, Table: "a", Name: "a_b", Column: "id", Part: 1, Parts: 1},
+			},
+		},
+		{
+			name: "composite referenced column",
+			tables: []inspect.Table{
+				{Name: "users"},
+				{Name: "orders", ForeignKeys: []inspect.ForeignKey{foreignKey("orders_user", []string{"tenant_id", "user_id"}, "users", "tenant_id", "id")}},
+			},
+			table:  "users",
+			column: "id",
+			want:   []Impact{{Kind: ReferenceImpact, Table: "orders", Name: "orders_user", Column: "user_id", Part: 2, Parts: 2}},
+		},
+		{
+			name: "composite referencing column",
+			tables: []inspect.Table{
+				{Name: "users"},
+				{
+					Name:        "orders",
+					Indexes:     []inspect.Index{columnsIndex("orders_tenant_user", false, "tenant_id", "user_id")},
+					ForeignKeys: []inspect.ForeignKey{foreignKey("orders_user", []string{"tenant_id", "user_id"}, "users", "tenant_id", "id")},
+				},
+			},
+			table:  "orders",
+			column: "user_id",
+			want: []Impact{
+				{Kind: IndexImpact, Table: "orders", Name: "orders_tenant_user", Column: "user_id", Part: 2, Parts: 2},
+				{Kind: ForeignKeyImpact, Table: "orders", Name: "orders_user", Column: "user_id", Part: 2, Parts: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ColumnImpact(inspect.Schema{Name: "public", Tables: tt.tables}, tt.table, tt.column)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ColumnImpact()\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
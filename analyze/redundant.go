@@ -0,0 +1,88 @@
+package analyze
+
+import (
+	"fmt"
+	"github.com/reallyliri/atlastui/inspect"
+	"slices"
+	"strings"
+)
+
+// RedundantIndex is an index whose lookups are served as well by another index of its table
+type RedundantIndex struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	Index  string `json:"index"`
+	// CoveredBy is the index to keep in its place
+	CoveredBy string `json:"covered_by"`
+	// Duplicate is set when both indexes have the same parts, otherwise the index is a prefix of the other
+	Duplicate bool `json:"duplicate"`
+}
+
+func (r RedundantIndex) Reason() string {
+	if r.Duplicate {
+		return fmt.Sprintf("duplicates %s", r.CoveredBy)
+	}
+	return fmt.Sprintf("prefix of %s", r.CoveredBy)
+}
+
+// RedundantIndexes lists the redundant indexes of the tables of the schema
+func RedundantIndexes(schema inspect.Schema) []RedundantIndex {
+	var redundant []RedundantIndex
+	for _, t := range schema.Tables {
+		for _, r := range redundantIndexes(t) {
+			r.Schema = schema.Name
+			redundant = append(redundant, r)
+		}
+	}
+	return redundant
+}
+
+// redundantIndexes compares the indexes of the table, including its primary key which is never redundant itself.
+// Parts match by column or by expression, and by sort order. A unique index is only covered by a unique index with
+// the same parts, as it enforces a constraint its prefixes do not.
+func redundantIndexes(t inspect.Table) []RedundantIndex {
+	indexes := Indexes(t)
+	var redundant []RedundantIndex
+	for i, idx := range indexes {
+		if len(idx.Parts) == 0 || (t.PrimaryKey != nil && i == 0) {
+			continue
+		}
+		for j, other := range indexes {
+			if i == j || len(other.Parts) == 0 {
+				continue
+			}
+			duplicate, ok := covers(other, idx, j < i)
+			if !ok {
+				continue
+			}
+			redundant = append(redundant, RedundantIndex{Table: t.Name, Index: idx.Name, CoveredBy: other.Name, Duplicate: duplicate})
+			break
+		}
+	}
+	return redundant
+}
+
+// covers tells whether the index serves every lookup of the covered one, and whether they are duplicates.
+// Of exact duplicates only the first is kept, so one of them is always reported.
+func covers(idx, covered inspect.Index, first bool) (duplicate bool, ok bool) {
+	switch {
+	case len(idx.Parts) == len(covered.Parts) && hasPrefix(idx, covered):
+		return true, idx.Unique && !covered.Unique || idx.Unique == covered.Unique && first
+	case len(idx.Parts) > len(covered.Parts) && hasPrefix(idx, covered):
+		return false, !covered.Unique
+	default:
+		return false, false
+	}
+}
+
+// hasPrefix is true when the first parts of the index are the parts of the prefix, in order
+func hasPrefix(idx, prefix inspect.Index) bool {
+	return slices.EqualFunc(idx.Parts[:len(prefix.Parts)], prefix.Parts, func(a, b inspect.IndexPart) bool {
+		return a.Desc == b.Desc && a.Column == b.Column && normalizeExpr(a.Expr) == normalizeExpr(b.Expr)
+	})
+}
+
+// normalizeExpr drops the whitespace differences of expressions inspected from different sources
+func normalizeExpr(expr string) string {
+	return strings.Join(strings.Fields(expr), " ")
+}
@@ -0,0 +1,142 @@
+package analyze
+
+import (
+	"github.com/reallyliri/atlastui/inspect"
+	"reflect"
+	"testing"
+)
+
+func columnsIndex(name string, unique bool, columns ...string) inspect.Index {
+	idx := inspect.Index{Name: name, Unique: unique}
+	for _, col := range columns {
+		idx.Parts = append(idx.Parts, inspect.IndexPart{Column: col})
+	}
+	return idx
+}
+
+func TestRedundantIndexes(t *testing.T) {
+	tests := []struct {
+		name       string
+		primaryKey *inspect.Index
+		indexes    []inspect.Index
+		want       []RedundantIndex
+	}{
+		{
+			name:    "prefix",
+			indexes: []inspect.Index{columnsIndex("a", false, "x"), columnsIndex("ab", false, "x", "y")},
+			want:    []RedundantIndex{{Table: "t", Index: "a", CoveredBy: "ab"}},
+		},
+		{
+			name:    "not a prefix",
+			indexes: []inspect.Index{columnsIndex("b", false, "y"), columnsIndex("ab", false, "x", "y"), columnsIndex("ba", false, "y", "x", "z")},
+			want:    []RedundantIndex{{Table: "t", Index: "b", CoveredBy: "ba"}},
+		},
+		{
+			name:    "non-unique prefix of a unique index",
+			indexes: []inspect.Index{columnsIndex("a", false, "x"), columnsIndex("ab_key", true, "x", "y")},
+			want:    []RedundantIndex{{Table: "t", Index: "a", CoveredBy: "ab_key"}},
+		},
+		{
+			name:    "unique prefix of a non-unique index",
+			indexes: []inspect.Index{columnsIndex("a_key", true, "x"), columnsIndex("ab", false, "x", "y")},
+		},
+		{
+			name:    "unique prefix of a unique index",
+			indexes: []inspect.Index{columnsIndex("a_key", true, "x"), columnsIndex("ab_key", true, "x", "y")},
+		},
+		{
+			name:    "exact duplicates",
+			indexes: []inspect.Index{columnsIndex("ab", false, "x", "y"), columnsIndex("ab_again", false, "x", "y")},
+			want:    []RedundantIndex{{Table: "t", Index: "ab_again", CoveredBy: "ab", Duplicate: true}},
+		},
+		{
+			name: "exact triplicates",
+			indexes: []inspect.Index{
+				columnsIndex("ab", false, "x", "y"),
+				columnsIndex("ab_again", false, "x", "y"),
+				columnsIndex("ab_once_more", false, "x", "y"),
+			},
+			want: []RedundantIndex{
+				{Table: "t", Index: "ab_again", CoveredBy: "ab", Duplicate: true},
+				{Table: "t", Index: "ab_once_more", CoveredBy: "ab", Duplicate: true},
+			},
+		},
+		{
+			name:    "duplicate of a unique index",
+			indexes: []inspect.Index{columnsIndex("ab", false, "x", "y"), columnsIndex("ab_key", true, "x", "y")},
+			want:    []RedundantIndex{{Table: "t", Index: "ab", CoveredBy: "ab_key", Duplicate: true}},
+		},
+		{
+			name:       "duplicate of the primary key",
+			primaryKey: &inspect.Index{Unique: true, Parts: []inspect.IndexPart{{Column: "x"}}},
+			indexes:    []inspect.Index{columnsIndex("a_key", true, "x"), columnsIndex("ab", false, "x", "y")},
+			want:       []RedundantIndex{{Table: "t", Index: "a_key", CoveredBy: "PRIMARY KEY", Duplicate: true}},
+		},
+		{
+			name:       "duplicate of a primary key not flagged unique",
+			primaryKey: &inspect.Index{Parts: []inspect.IndexPart{{Column: "x"}}},
+			indexes:    []inspect.Index{columnsIndex("a_key", true, "x")},
+			want:       []RedundantIndex{{Table: "t", Index: "a_key", CoveredBy: "PRIMARY KEY", Duplicate: true}},
+		},
+		{
+			name:       "prefix of the primary key",
+			primaryKey: &inspect.Index{Name: "t_pkey", Unique: true, Parts: []inspect.IndexPart{{Column: "x"}, {Column: "y"}}},
+			indexes:    []inspect.Index{columnsIndex("a", false, "x")},
+			want:       []RedundantIndex{{Table: "t", Index: "a", CoveredBy: "t_pkey"}},
+		},
+		{
+			name: "desc and asc",
+			indexes: []inspect.Index{
+				{Name: "a_desc", Parts: []inspect.IndexPart{{Column: "x", Desc: true}}},
+				{Name: "ab", Parts: []inspect.IndexPart{{Column: "x"}, {Column: "y"}}},
+			},
+		},
+		{
+			name: "desc prefix",
+			indexes: []inspect.Index{
+				{Name: "a_desc", Parts: []inspect.IndexPart{{Column: "x", Desc: true}}},
+				{Name: "ab_desc", Parts: []inspect.IndexPart{{Column: "x", Desc: true}, {Column: "y"}}},
+			},
+			want: []RedundantIndex{{Table: "t", Index: "a_desc", CoveredBy: "ab_desc"}},
+		},
+		{
+			name: "expressions differing in whitespace",
+			indexes: []inspect.Index{
+				{Name: "lower", Parts: []inspect.IndexPart{{Expr: "lower(email)"}}},
+				{Name: "lower_spaced", Parts: []inspect.IndexPart{{Expr: " lower(email)\n"}}},
+				{Name: "lower_name", Parts: []inspect.IndexPart{{Expr: "lower( email )"}, {Column: "name"}}},
+			},
+			want: []RedundantIndex{{Table: "t", Index: "lower_spaced", CoveredBy: "lower", Duplicate: true}},
+		},
+		{
+			name: "expression and column",
+			indexes: []inspect.Index{
+				{Name: "lower", Parts: []inspect.IndexPart{{Expr: "lower(email)"}}},
+				columnsIndex("email", false, "email"),
+			},
+		},
+		{
+			name:    "no parts",
+			indexes: []inspect.Index{{Name: "empty"}, {Name: "empty_again"}, columnsIndex("a", false, "x")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := inspect.Table{Name: "t", PrimaryKey: tt.primaryKey, Indexes: tt.indexes}
+			if got := redundantIndexes(table); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redundantIndexes()\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedundantIndexesOfSchema(t *testing.T) {
+	schema := inspect.Schema{Name: "public", Tables: []inspect.Table{
+		{Name: "a", Indexes: []inspect.Index{columnsIndex("a_x", false, "x"), columnsIndex("a_xy", false, "x", "y")}},
+		{Name: "b", Indexes: []inspect.Index{columnsIndex("b_x", false, "x")}},
+	}}
+	want := []RedundantIndex{{Schema: "public", Table: "a", Index: "a_x", CoveredBy: "a_xy"}}
+	if got := RedundantIndexes(schema); !reflect.DeepEqual(got, want) {
+		t.Errorf("RedundantIndexes() = %+v, want %+v", got, want)
+	}
+}
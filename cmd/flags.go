@@ -36,25 +36,73 @@ func initFlags(set *pflag.FlagSet) {
 		&params.FromFilePath,
 		"file", "f",
 		"",
-		"optional path to a file containing the output of 'atlas schema inspect' command. Currently only supports JSON format",
+		"optional path to a file containing the output of 'atlas schema inspect' command in JSON format, a DBML file (.dbml extension) or an atlas HCL/SQL schema file (.hcl/.sql extension, requires --dev-url). Use '-' to read from stdin",
+	)
+
+	set.StringVar(
+		&flags.snapshot,
+		"snapshot",
+		"",
+		"optional id or path of a previously saved snapshot to load, see the 'snapshots' command",
+	)
+
+	set.StringVar(
+		&flags.dirURL,
+		"dir",
+		"",
+		"optional url of the migration dir to browse along with its status against the database, defaults to the migration dir of the env",
 	)
 
 	set.StringVar(&params.Env, "env", "", "set which env from the config file to use")
 	set.StringVarP(&params.ConfigURL, "config", "c", "", "select config (project) file using URL format")
 
-	set.StringVar(&params.AtlasCliPath, "atlas", "", "path of the atlas cli, defaults to look in PATH")
+	set.StringVar(&params.AtlasCliPath, "atlas", "", "path of the atlas cli, defaults to look in PATH. Not required when loading from --file")
+}
+
+// initUIFlags sets flags only relevant to the interactive ui
+func initUIFlags(set *pflag.FlagSet) {
+	set.BoolVarP(
+		&flags.watch,
+		"watch", "w",
+		false,
+		"reload the schema whenever --file changes",
+	)
+	set.BoolVar(
+		&flags.stats,
+		"stats",
+		false,
+		"fetch table statistics (row counts, sizes) from the database, postgres, mysql and sqlite are supported",
+	)
+	set.StringVar(
+		&flags.compareWith,
+		"compare-with",
+		"",
+		"optional schema file or atlas url to compare the inspected schema with, e.g. schema.hcl against the database. Planning a migration to it requires an atlas schema file or url",
+	)
+	set.IntVar(
+		&flags.pageSize,
+		"page-size",
+		50,
+		"number of rows per page in the data tab, which previews the rows of live databases",
+	)
+	set.IntVar(
+		&flags.sampleSize,
+		"sample-size",
+		100_000,
+		"number of rows read when profiling a column, larger tables are sampled at random where the database allows it cheaply",
+	)
 }
 
-func verifyFileExists(fpath string) error {
+func verifyFileExists(fpath string, description string) error {
 	fi, err := os.Stat(fpath)
 	if errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("atlas cli not found at '%s'", fpath)
+		return fmt.Errorf("%s not found at '%s'", description, fpath)
 	}
 	if err != nil {
-		return fmt.Errorf("failed to access atlas cli at '%s': %w", fpath, err)
+		return fmt.Errorf("failed to access %s at '%s': %w", description, fpath, err)
 	}
 	if !fi.Mode().IsRegular() {
-		return fmt.Errorf("atlas cli at '%s' is not a regular file", fpath)
+		return fmt.Errorf("%s at '%s' is not a regular file", description, fpath)
 	}
 	return nil
 }
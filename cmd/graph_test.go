@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"github.com/reallyliri/atlastui/analyze"
+	"testing"
+)
+
+func TestWriteGraphs(t *testing.T) {
+	graphs := []analyze.Graph{
+		{Schema: "a", LoadOrder: []string{"users", "orders"}, Hubs: []analyze.Hub{{Table: "users", References: 1}}},
+		{Schema: "b", LoadOrder: []string{"logs"}, Isolated: []string{"logs"}},
+	}
+	want := `Schema a
+
+Load order (reversed to truncate)
+    1. users
+    2. orders
+
+Cycles
+  none
+
+Isolated tables
+  none
+
+Hub tables
+  users (1 reference)
+
+Schema b
+
+Load order (reversed to truncate)
+    1. logs
+
+Cycles
+  none
+
+Isolated tables
+  logs
+
+Hub tables
+  none
+`
+	var out bytes.Buffer
+	if err := writeGraphs(&out, graphs, "text"); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
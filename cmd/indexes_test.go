@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"github.com/reallyliri/atlastui/analyze"
+	"testing"
+)
+
+func TestWriteRedundantIndexes(t *testing.T) {
+	redundant := []analyze.RedundantIndex{{Schema: "public", Table: "users", Index: "users_email", CoveredBy: "users_email_name"}}
+	tests := []struct {
+		name      string
+		redundant []analyze.RedundantIndex
+		format    string
+		want      string
+	}{
+		{name: "none", format: "text", want: "no redundant indexes\n"},
+		{name: "none as json", format: "json", want: "[]\n"},
+		{
+			name:      "text",
+			redundant: redundant,
+			format:    "text",
+			want:      "SCHEMA  TABLE  INDEX        REASON\npublic  users  users_email  prefix of users_email_name\n",
+		},
+		{
+			name:      "json",
+			redundant: redundant,
+			format:    "json",
+			want: `[
+  {
+    "schema": "public",
+    "table": "users",
+    "index": "users_email",
+    "covered_by": "users_email_name",
+    "duplicate": false
+  }
+]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeRedundantIndexes(&out, tt.redundant, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"context"
+	"fmt"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/migrate"
+	"github.com/reallyliri/atlastui/project"
+	"github.com/reallyliri/atlastui/tui"
+	"github.com/samber/lo"
+	"io"
+	"strings"
+)
+
+// migrationName is the description part of migration files written by atlastui
+const migrationName = "atlastui"
+
+// planMigration plans the sql migrating one compared schema to match the other. Each is named by its env, by the
+// --compare-with source, or is empty for the inspected database or schema file
+func planMigration(proj *project.Project) tui.PlanLoader {
+	return func(ctx context.Context, from, to string, stderr io.Writer) (string, error) {
+		fromURL, fromEnv, err := migrationSource(proj, from)
+		if err != nil {
+			return "", err
+		}
+		toURL, toEnv, err := migrationSource(proj, to)
+		if err != nil {
+			return "", err
+		}
+		if len(params.Schema) == 0 && toEnv != nil {
+			err = toEnv.Require("schemas")
+		}
+		if err == nil && len(params.Exclude) == 0 && toEnv != nil {
+			err = toEnv.Require("exclude")
+		}
+		if err != nil {
+			return "", err
+		}
+		if err = resolveAtlasCli("plan a migration"); err != nil {
+			return "", err
+		}
+		envs := lo.FilterMap([]*project.Env{toEnv, fromEnv}, func(env *project.Env, _ int) (project.Env, bool) {
+			return lo.FromPtr(env), env != nil
+		})
+		devURL, err := envDevURL(envs...)
+		if err != nil {
+			return "", err
+		}
+		if devURL == "" && (strings.HasPrefix(fromURL, "file://") || strings.HasPrefix(toURL, "file://")) {
+			return "", fmt.Errorf("planning with a schema file requires a dev database, set --dev-url")
+		}
+		diffParams := &inspect.DiffSQLParams{
+			From:    fromURL,
+			To:      toURL,
+			DevURL:  devURL,
+			Schema:  params.Schema,
+			Exclude: params.Exclude,
+			Vars:    params.Vars,
+		}
+		if len(diffParams.Schema) == 0 && toEnv != nil {
+			diffParams.Schema = toEnv.Schemas
+		}
+		if len(diffParams.Exclude) == 0 && toEnv != nil {
+			diffParams.Exclude = toEnv.Exclude
+		}
+		return inspect.DiffSQL(ctx, params.AtlasCliPath, diffParams, stderr)
+	}
+}
+
+// migrationSource is the atlas url of a compared schema, along with its env when it is one
+func migrationSource(proj *project.Project, name string) (string, *project.Env, error) {
+	switch {
+	case name == "" && params.URL != "":
+		return params.URL, nil, nil
+	case name == "" && params.FromFilePath != "" && inspect.IsSchemaFile(params.FromFilePath):
+		url, err := schemaURL(params.FromFilePath)
+		return url, nil, err
+	case name == "":
+		return "", nil, fmt.Errorf("planning requires a database or an atlas schema file, set --url, --env or --file")
+	case name == flags.compareWith:
+		if !strings.Contains(name, "://") && !inspect.IsSchemaFile(name) {
+			return "", nil, fmt.Errorf("planning to '%s' requires an atlas schema file (.hcl/.sql) or url", name)
+		}
+		url, err := schemaURL(name)
+		return url, nil, err
+	}
+	if proj == nil {
+		return "", nil, fmt.Errorf("env '%s' is not defined, no project file found", name)
+	}
+	env, ok := proj.Env(name)
+	if !ok {
+		return "", nil, fmt.Errorf("env '%s' is not defined in %s", name, proj.Path)
+	}
+	if env.URL == "" {
+		return "", nil, fmt.Errorf("env '%s' has no url to diff", env.Name)
+	}
+	if err := env.Require("url"); err != nil {
+		return "", nil, err
+	}
+	return env.URL, &env, nil
+}
+
+// writeMigration writes a migration file into the migration dir of --dir or of the env, the same dir status and lint read,
+// and re-hashes the dir
+func writeMigration(proj *project.Project) tui.MigrationWriter {
+	return func(ctx context.Context, envName, sql string) (string, error) {
+		dirURL, err := migrationDirURL(proj, envName)
+		if err != nil {
+			return "", err
+		}
+		if err = resolveAtlasCli("hash the migration dir"); err != nil {
+			return "", err
+		}
+		fpath, err := migrate.WriteFile(dirURL, migrationName, sql)
+		if err != nil {
+			return "", err
+		}
+		if err = inspect.HashMigrationDir(ctx, params.AtlasCliPath, dirURL); err != nil {
+			return fpath, fmt.Errorf("migration written to %s, but hashing the migration dir failed: %w", fpath, err)
+		}
+		return fpath, nil
+	}
+}
+
+// migrationStatus runs 'atlas migrate status' for the migration dir of --dir or of the env, against the inspected database
+func migrationStatus(proj *project.Project) tui.MigrationStatusLoader {
+	return func(ctx context.Context, env string) (*migrate.Status, error) {
+		if err := resolveAtlasCli("read the migration status"); err != nil {
+			return nil, err
+		}
+		dirURL, err := migrationDirURL(proj, env)
+		if err != nil {
+			return nil, err
+		}
+		client, err := atlasexec.NewClient("", params.AtlasCliPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create atlas client: %w", err)
+		}
+		status, err := client.MigrateStatus(ctx, &atlasexec.MigrateStatusParams{
+			Env:       env,
+			ConfigURL: params.ConfigURL,
+			DirURL:    dirURL,
+			URL:       params.URL,
+			Vars:      params.Vars,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return migrate.NewStatus(dirURL, status), nil
+	}
+}
+
+// migrationLint runs 'atlas migrate lint' over all files of the migration dir of --dir or of the env
+func migrationLint(proj *project.Project) tui.LintLoader {
+	return func(ctx context.Context, envName string) (*migrate.Lint, error) {
+		if err := resolveAtlasCli("lint the migration dir"); err != nil {
+			return nil, err
+		}
+		dirURL, err := migrationDirURL(proj, envName)
+		if err != nil {
+			return nil, err
+		}
+		devURL := params.DevURL
+		// atlas resolves the dev url of the env itself when it cannot be evaluated here
+		if env, ok := currentEnv(proj, envName); devURL == "" && ok && env.Resolved("dev") {
+			devURL = env.DevURL
+		}
+		if devURL == "" && envName == "" {
+			return nil, fmt.Errorf("linting replays the migration dir on a dev database, set --dev-url")
+		}
+		files, err := migrate.CountFiles(dirURL)
+		if err != nil {
+			return nil, err
+		}
+		client, err := atlasexec.NewClient("", params.AtlasCliPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create atlas client: %w", err)
+		}
+		report, err := client.MigrateLint(ctx, &atlasexec.MigrateLintParams{
+			Env:       envName,
+			ConfigURL: params.ConfigURL,
+			DevURL:    devURL,
+			DirURL:    dirURL,
+			Latest:    uint64(files),
+			Vars:      params.Vars,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return migrate.NewLint(dirURL, report), nil
+	}
+}
+
+// migrationDirURL is the migration dir of --dir, falling back to the one of the env
+func migrationDirURL(proj *project.Project, envName string) (string, error) {
+	dirURL := flags.dirURL
+	if env, ok := currentEnv(proj, envName); dirURL == "" && ok {
+		if err := env.Require("dir"); err != nil {
+			return "", fmt.Errorf("%w, set --dir", err)
+		}
+		dirURL = env.MigrationDir
+	}
+	dirURL, _ = lo.Coalesce(dirURL, migrate.DefaultDirURL)
+	return dirURL, nil
+}
+
+// envDevURL is the dev database of --dev-url, falling back to the first of the envs setting one
+func envDevURL(envs ...project.Env) (string, error) {
+	if params.DevURL != "" {
+		return params.DevURL, nil
+	}
+	for _, env := range envs {
+		if env.DevURL == "" {
+			continue
+		}
+		if err := env.Require("dev"); err != nil {
+			return "", fmt.Errorf("%w, set --dev-url", err)
+		}
+		return env.DevURL, nil
+	}
+	return "", nil
+}
+
+// currentEnv is the env of the project by its name, the name is passed along by the tui rather than read from params
+func currentEnv(proj *project.Project, name string) (project.Env, bool) {
+	if proj == nil {
+		return project.Env{}, false
+	}
+	return proj.Env(name)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/reallyliri/atlastui/snapshot"
+	"github.com/spf13/cobra"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+var snapshotsCmd = &cobra.Command{
+	Use:          "snapshots",
+	Short:        "List the snapshots saved from previous inspections",
+	Long:         fmt.Sprintf("Each successful inspection in %s is saved as a snapshot, which can be reopened with --snapshot.", toolName),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := snapshot.NewStore()
+		if err != nil {
+			return err
+		}
+		entries, err := store.ListAll()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTAKEN AT\tSOURCE")
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", entry.ID, entry.TakenAt.Local().Format(time.DateTime), entry.Source)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotsCmd)
+}
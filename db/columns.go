@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ColumnDefault reads the default of a column, which atlas inspection does not report. It is nil when the column has none
+func ColumnDefault(ctx context.Context, dbURL, schema, table, column string) (*string, error) {
+	conn, dialect, err := Open(dbURL)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var query string
+	args := []any{schema, table, column}
+	switch dialect {
+	case Postgres:
+		query = `SELECT column_default FROM information_schema.columns
+WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2 AND column_name = $3`
+	case MySQL:
+		query = `SELECT COLUMN_DEFAULT FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ? AND COLUMN_NAME = ?`
+	default:
+		// a sqlite database has a single schema
+		query = `SELECT dflt_value FROM pragma_table_info(?) WHERE name = ?`
+		args = args[1:]
+	}
+	var value sql.NullString
+	err = conn.QueryRowContext(ctx, query, args...).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("column %s of %s was not found in the database", column, table)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the default of %s: %w", column, err)
+	}
+	if !value.Valid {
+		return nil, nil
+	}
+	return &value.String, nil
+}
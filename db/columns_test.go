@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestColumnDefault(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "test.db")
+	conn, err := sql.Open(string(SQLite), fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, err = conn.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, status TEXT DEFAULT 'new', total REAL DEFAULT 0, created_at TEXT DEFAULT CURRENT_TIMESTAMP)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		column  string
+		want    string
+		wantNil bool
+		wantErr bool
+	}{
+		{column: "status", want: "'new'"},
+		{column: "total", want: "0"},
+		{column: "created_at", want: "CURRENT_TIMESTAMP"},
+		{column: "id", wantNil: true},
+		{column: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			got, err := ColumnDefault(context.Background(), "sqlite://"+fpath, "main", "orders", tt.column)
+			switch {
+			case tt.wantErr:
+				if err == nil {
+					t.Error("expected an error")
+				}
+			case err != nil:
+				t.Fatal(err)
+			case tt.wantNil && got != nil:
+				t.Errorf("ColumnDefault() = %q, want none", *got)
+			case !tt.wantNil && (got == nil || *got != tt.want):
+				t.Errorf("ColumnDefault() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// maxHistory is the number of queries kept per connection
+const maxHistory = 100
+
+// QueryHistory keeps the queries ran on each connection on disk, a file per connection
+type QueryHistory struct {
+	dir string
+}
+
+func NewQueryHistory() (*QueryHistory, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return &QueryHistory{dir: filepath.Join(cacheDir, "atlastui", "queries")}, nil
+}
+
+// List returns the queries ran on the given connection, newest first
+func (h *QueryHistory) List(source string) ([]string, error) {
+	raw, err := os.ReadFile(h.path(source))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query history: %w", err)
+	}
+	var queries []string
+	if err = json.Unmarshal(raw, &queries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal query history: %w", err)
+	}
+	return queries, nil
+}
+
+// Add puts the query first in the history of the connection, dropping older runs of it
+func (h *QueryHistory) Add(source, query string) error {
+	queries, err := h.List(source)
+	if err != nil {
+		return err
+	}
+	history := []string{query}
+	for _, q := range queries {
+		if q != query && len(history) < maxHistory {
+			history = append(history, q)
+		}
+	}
+	raw, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query history: %w", err)
+	}
+	if err = os.MkdirAll(h.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create query history directory: %w", err)
+	}
+	if err = os.WriteFile(h.path(source), raw, 0600); err != nil {
+		return fmt.Errorf("failed to write query history: %w", err)
+	}
+	return nil
+}
+
+func (h *QueryHistory) path(source string) string {
+	hash := sha256.Sum256([]byte(source))
+	return filepath.Join(h.dir, hex.EncodeToString(hash[:])[:16]+".json")
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Page is a page of the rows of a table, NULL values are nil
+type Page struct {
+	Columns []string
+	Rows    [][]*string
+	Offset  int
+	// HasMore is set when there are rows past this page
+	HasMore bool
+}
+
+// FetchPage reads a page of rows of the table within a read-only transaction, which is always rolled back.
+// Rows are ordered by the given columns, typically the primary key, so paging is stable.
+func FetchPage(ctx context.Context, dbURL, schema, table string, orderBy []string, offset, limit int) (*Page, error) {
+	conn, dialect, err := Open(dbURL)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf("SELECT * FROM %s", dialect.Table(schema, table))
+	if len(orderBy) > 0 {
+		quoted := make([]string, 0, len(orderBy))
+		for _, column := range orderBy {
+			quoted = append(quoted, dialect.Quote(column))
+		}
+		query += " ORDER BY " + strings.Join(quoted, ", ")
+	}
+	// one more row tells whether there is a next page
+	query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit+1, offset)
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	page, err := readPage(rows, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rows of %s: %w", table, err)
+	}
+	page.Offset = offset
+	return page, nil
+}
+
+// readPage reads up to limit rows, values are read as strings
+func readPage(rows *sql.Rows, limit int) (*Page, error) {
+	page := &Page{}
+	var err error
+	if page.Columns, err = rows.Columns(); err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		if len(page.Rows) == limit {
+			page.HasMore = true
+			break
+		}
+		values := make([]sql.NullString, len(page.Columns))
+		dests := make([]any, len(values))
+		for i := range values {
+			dests[i] = &values[i]
+		}
+		if err = rows.Scan(dests...); err != nil {
+			return nil, err
+		}
+		row := make([]*string, len(values))
+		for i, value := range values {
+			if value.Valid {
+				row[i] = &value.String
+			}
+		}
+		page.Rows = append(page.Rows, row)
+	}
+	return page, rows.Err()
+}
@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/samber/lo"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// histogramBuckets is the number of equal-width buckets of a histogram
+const histogramBuckets = 10
+
+// ValueKind is how the values of a column compare, as detected from the values themselves
+type ValueKind string
+
+const (
+	TextValues    ValueKind = "text"
+	NumericValues ValueKind = "numeric"
+	TimeValues    ValueKind = "time"
+)
+
+// timeLayouts are the formats the drivers return dates and timestamps in
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+// Profile summarizes the values of a column. On large tables it is computed over a sample of the rows
+type Profile struct {
+	Rows     int
+	Sampling Sampling
+	// TableRows is the number of rows of the table, exact when all rows were read and an estimate of the database
+	// otherwise, or UnknownRows when the database keeps none
+	TableRows int64
+	Nulls     int
+	Distinct  int
+	Kind      ValueKind
+	Min       string
+	Max       string
+	Top       []ValueCount
+	// Histogram is set for numeric and time values only
+	Histogram []Bucket
+}
+
+// ValueCount is a value along with the number of rows holding it
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// Bucket counts the values within [From, To), the last bucket includes its upper bound
+type Bucket struct {
+	From  string
+	To    string
+	Count int
+}
+
+// NullRatio is the share of NULL values out of the rows read
+func (p *Profile) NullRatio() float64 {
+	if p.Rows == 0 {
+		return 0
+	}
+	return float64(p.Nulls) / float64(p.Rows)
+}
+
+// ProfileColumn profiles the values of a column within a read-only transaction, which is always rolled back.
+// The table is never read in full: tables estimated to have more than sampleSize rows are sampled at random where
+// the database allows it cheaply, and only their first sampleSize rows are read otherwise.
+func ProfileColumn(ctx context.Context, dbURL, schema, table, column string, sampleSize, topN int) (*Profile, error) {
+	conn, dialect, err := Open(dbURL)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	s := &sampler{tx: tx, dialect: dialect, schema: schema, table: table, column: column}
+	estimate := EstimateRows(ctx, tx, dialect, schema, table)
+	var values []*string
+	var sampling Sampling
+	if estimate > int64(sampleSize) {
+		values, sampling, err = s.randomRows(ctx, sampleSize, estimate)
+	} else {
+		values, sampling, err = s.firstRows(ctx, sampleSize)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s of %s: %w", column, table, err)
+	}
+	profile := NewProfile(values, topN)
+	profile.Sampling = sampling
+	switch {
+	case sampling == AllRows:
+		profile.TableRows = int64(profile.Rows)
+	case estimate > int64(profile.Rows):
+		profile.TableRows = estimate
+	default:
+		// the estimate is stale, as more rows than estimated were read
+		profile.TableRows = UnknownRows
+	}
+	return profile, nil
+}
+
+// NewProfile profiles the given values, NULL values are nil
+func NewProfile(values []*string, topN int) *Profile {
+	p := &Profile{Rows: len(values)}
+	counts := make(map[string]int)
+	var nonNull []string
+	for _, value := range values {
+		if value == nil {
+			p.Nulls++
+			continue
+		}
+		if counts[*value] == 0 {
+			nonNull = append(nonNull, *value)
+		}
+		counts[*value]++
+	}
+	p.Distinct = len(counts)
+
+	p.Top = lo.Map(nonNull, func(value string, _ int) ValueCount { return ValueCount{Value: value, Count: counts[value]} })
+	slices.SortStableFunc(p.Top, func(a, b ValueCount) int { return b.Count - a.Count })
+	p.Top = p.Top[:lo.Min([]int{topN, len(p.Top)})]
+
+	if len(nonNull) == 0 {
+		p.Kind = TextValues
+		return p
+	}
+	if numbers, ok := parseAll(nonNull, parseNumber); ok {
+		p.Kind = NumericValues
+		p.Min, p.Max, p.Histogram = numericRange(numbers, counts, nonNull)
+		return p
+	}
+	if times, ok := parseAll(nonNull, parseTime); ok {
+		p.Kind = TimeValues
+		p.Min, p.Max, p.Histogram = timeRange(times, counts, nonNull)
+		return p
+	}
+	p.Kind = TextValues
+	p.Min, p.Max = slices.Min(nonNull), slices.Max(nonNull)
+	return p
+}
+
+func parseAll[T any](values []string, parse func(string) (T, bool)) ([]T, bool) {
+	parsed := make([]T, 0, len(values))
+	for _, value := range values {
+		v, ok := parse(value)
+		if !ok {
+			return nil, false
+		}
+		parsed = append(parsed, v)
+	}
+	return parsed, true
+}
+
+func parseNumber(value string) (float64, bool) {
+	n, err := strconv.ParseFloat(value, 64)
+	return n, err == nil && !math.IsNaN(n) && !math.IsInf(n, 0)
+}
+
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// numericRange returns the min and max values as they were read, along with a histogram of the distinct values weighted by their counts
+func numericRange(numbers []float64, counts map[string]int, values []string) (string, string, []Bucket) {
+	minIdx, maxIdx := 0, 0
+	for i, n := range numbers {
+		if n < numbers[minIdx] {
+			minIdx = i
+		}
+		if n > numbers[maxIdx] {
+			maxIdx = i
+		}
+	}
+	low, high := numbers[minIdx], numbers[maxIdx]
+	buckets := histogram(low, high, func(v float64) string { return strconv.FormatFloat(v, 'g', 6, 64) })
+	for i, n := range numbers {
+		buckets[bucketOf(n, low, high, len(buckets))].Count += counts[values[i]]
+	}
+	return values[minIdx], values[maxIdx], buckets
+}
+
+func timeRange(times []time.Time, counts map[string]int, values []string) (string, string, []Bucket) {
+	minIdx, maxIdx := 0, 0
+	for i, t := range times {
+		if t.Before(times[minIdx]) {
+			minIdx = i
+		}
+		if t.After(times[maxIdx]) {
+			maxIdx = i
+		}
+	}
+	low, high := float64(times[minIdx].Unix()), float64(times[maxIdx].Unix())
+	layout := histogramTimeLayout(high - low)
+	buckets := histogram(low, high, func(v float64) string { return time.Unix(int64(v), 0).UTC().Format(layout) })
+	for i, t := range times {
+		buckets[bucketOf(float64(t.Unix()), low, high, len(buckets))].Count += counts[values[i]]
+	}
+	return values[minIdx], values[maxIdx], buckets
+}
+
+// histogramTimeLayout shows the time of day only when the values span a few days
+func histogramTimeLayout(span float64) string {
+	if span < 3*24*60*60 {
+		return "2006-01-02 15:04"
+	}
+	return "2006-01-02"
+}
+
+// histogram splits [low, high] into equal-width buckets, a single one when all values are equal
+func histogram(low, high float64, label func(float64) string) []Bucket {
+	if low == high {
+		return []Bucket{{From: label(low), To: label(high)}}
+	}
+	width := (high - low) / histogramBuckets
+	buckets := make([]Bucket, histogramBuckets)
+	for i := range buckets {
+		buckets[i].From = label(low + float64(i)*width)
+		buckets[i].To = label(low + float64(i+1)*width)
+	}
+	return buckets
+}
+
+func bucketOf(v, low, high float64, n int) int {
+	if n == 1 {
+		return 0
+	}
+	return min(int((v-low)/(high-low)*float64(n)), n-1)
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestProfileColumnSampling(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "test.db")
+	conn, err := sql.Open(string(SQLite), fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, err = conn.Exec(`
+		CREATE TABLE events (id INTEGER PRIMARY KEY, kind TEXT);
+		CREATE TABLE tags (name TEXT PRIMARY KEY) WITHOUT ROWID;
+		WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 1000)
+		INSERT INTO events SELECT i, CASE WHEN i % 2 = 0 THEN 'even' END FROM n;
+		WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 1000)
+		INSERT INTO tags SELECT 'tag' || i FROM n;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name          string
+		table         string
+		column        string
+		sampleSize    int
+		wantSampling  Sampling
+		wantRows      int
+		wantTableRows int64
+	}{
+		{name: "all rows", table: "events", column: "kind", sampleSize: 1000, wantSampling: AllRows, wantRows: 1000, wantTableRows: 1000},
+		{name: "random rows", table: "events", column: "kind", sampleSize: 100, wantSampling: RandomRows, wantRows: 100, wantTableRows: 1000},
+		{name: "without rowid", table: "tags", column: "name", sampleSize: 100, wantSampling: FirstRows, wantRows: 100, wantTableRows: UnknownRows},
+		{name: "all rows without rowid", table: "tags", column: "name", sampleSize: 1000, wantSampling: AllRows, wantRows: 1000, wantTableRows: 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := ProfileColumn(context.Background(), "sqlite://"+fpath, "main", tt.table, tt.column, tt.sampleSize, 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if profile.Sampling != tt.wantSampling || profile.Rows != tt.wantRows || profile.TableRows != tt.wantTableRows {
+				t.Errorf("sampling = %s of %d rows out of %d, want %s of %d rows out of %d",
+					profile.Sampling, profile.Rows, profile.TableRows, tt.wantSampling, tt.wantRows, tt.wantTableRows)
+			}
+		})
+	}
+}
+
+func TestRandomKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int
+		low, high int64
+		wantLen   int
+	}{
+		{name: "fewer keys than the span", n: 10, low: 5, high: 1000, wantLen: 10},
+		{name: "span of n keys", n: 10, low: -4, high: 5, wantLen: 10},
+		{name: "smaller span", n: 10, low: 1, high: 3, wantLen: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := randomKeys(tt.n, tt.low, tt.high)
+			if len(keys) != tt.wantLen {
+				t.Fatalf("got %d keys, want %d", len(keys), tt.wantLen)
+			}
+			if !slices.IsSorted(keys) || len(slices.Compact(slices.Clone(keys))) != len(keys) {
+				t.Errorf("keys %v are not sorted and distinct", keys)
+			}
+			if keys[0] < tt.low || keys[len(keys)-1] > tt.high {
+				t.Errorf("keys %v are out of [%d, %d]", keys, tt.low, tt.high)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// literals and quoted identifiers may hold any word, e.g. a column named "delete", and are matched along with comments
+	// so that comment markers within literals, and quotes within comments, are not mistaken
+	literalsPattern = regexp.MustCompile("(?s)'(?:[^']|'')*'|\"[^\"]*\"|`[^`]*`|--[^\n]*|/\\*.*?\\*/")
+	// dataChangingPattern catches writes nested in read queries, e.g. WITH d AS (DELETE ...) SELECT
+	dataChangingPattern = regexp.MustCompile(`(?i)\b(INSERT|UPDATE|DELETE|MERGE|UPSERT|TRUNCATE|DROP|ALTER|CREATE|GRANT|REVOKE|CALL|COPY|LOCK|VACUUM|ATTACH|DETACH|PRAGMA|INTO)\b`)
+)
+
+// readQueryKeywords are the statements allowed to start a query
+var readQueryKeywords = []string{"SELECT", "WITH"}
+
+// CheckReadOnly rejects anything but a single SELECT query.
+// It is a guard on top of the read-only transaction, to fail early with a clear error.
+func CheckReadOnly(query string) error {
+	stripped := literalsPattern.ReplaceAllStringFunc(query, func(match string) string {
+		if strings.HasPrefix(match, "--") || strings.HasPrefix(match, "/*") {
+			return " "
+		}
+		return "''"
+	})
+	stripped = strings.TrimSuffix(strings.TrimSpace(stripped), ";")
+	if stripped == "" {
+		return fmt.Errorf("the query is empty")
+	}
+	if strings.Contains(stripped, ";") {
+		return fmt.Errorf("only a single query can run at a time")
+	}
+	first := strings.ToUpper(strings.Fields(stripped)[0])
+	first = strings.TrimLeft(first, "(")
+	allowed := false
+	for _, keyword := range readQueryKeywords {
+		allowed = allowed || strings.HasPrefix(first, keyword)
+	}
+	if !allowed {
+		return fmt.Errorf("only SELECT queries are allowed, the console is read-only")
+	}
+	if match := dataChangingPattern.FindString(stripped); match != "" {
+		return fmt.Errorf("%s is not allowed, the console is read-only", strings.ToUpper(match))
+	}
+	return nil
+}
+
+// Query runs a read-only query within a transaction that is always rolled back, reading up to limit rows
+func Query(ctx context.Context, dbURL, query string, limit int) (*Page, error) {
+	if err := CheckReadOnly(query); err != nil {
+		return nil, err
+	}
+	conn, _, err := Open(dbURL)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin a read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return readPage(rows, limit)
+}
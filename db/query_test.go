@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckReadOnly(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		// wantErr is a part of the expected error, none is expected when empty
+		wantErr string
+	}{
+		{name: "select", query: "SELECT * FROM users"},
+		{name: "trailing semicolon", query: "select * from users;"},
+		{name: "with", query: "WITH active AS (SELECT * FROM users) SELECT * FROM active"},
+		{name: "parenthesized", query: "(SELECT 1) UNION (SELECT 2)"},
+		{name: "keyword in literal", query: "SELECT * FROM users WHERE name = 'delete'"},
+		{name: "escaped quote in literal", query: "SELECT 'it''s; drop table users'"},
+		{name: "keyword in quoted identifier", query: `SELECT "update" FROM users`},
+		{name: "keyword in backquoted identifier", query: "SELECT `drop` FROM users"},
+		{name: "keyword in line comment", query: "SELECT 1 -- then delete it's rows\n"},
+		{name: "keyword in block comment", query: "SELECT /* insert; */ 1"},
+		{name: "semicolon in literal", query: "SELECT * FROM users WHERE name = 'a;b'"},
+		{name: "empty", query: " ; ", wantErr: "empty"},
+		{name: "comment only", query: "-- SELECT 1", wantErr: "empty"},
+		{name: "delete", query: "DELETE FROM users", wantErr: "only SELECT"},
+		{name: "delete in with", query: "WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", wantErr: "DELETE"},
+		{name: "select into", query: "SELECT * INTO backup FROM users", wantErr: "INTO"},
+		{name: "multiple selects", query: "SELECT 1; SELECT 2", wantErr: "single query"},
+		{name: "select then drop", query: "SELECT 1; DROP TABLE users", wantErr: "single query"},
+		{name: "write after literal", query: "SELECT ';'; DELETE FROM users", wantErr: "single query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckReadOnly(tt.query)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("CheckReadOnly() = %v, want no error", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("CheckReadOnly() = %v, want an error with %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// testDatabase is the url of a sqlite database of users, the second of which has no email
+func testDatabase(t *testing.T) string {
+	fpath := filepath.Join(t.TempDir(), "test.db")
+	conn, err := sql.Open(string(SQLite), fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, err = conn.Exec(`
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT);
+		INSERT INTO users VALUES (3, 'carol', 'carol@example.com'), (1, 'alice', 'alice@example.com'), (2, 'bob', NULL);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "sqlite://" + fpath
+}
+
+// values are the values of the rows of the page, NULL values as NULL
+func values(page *Page) [][]string {
+	rows := make([][]string, 0, len(page.Rows))
+	for _, row := range page.Rows {
+		values := make([]string, 0, len(row))
+		for _, value := range row {
+			if value == nil {
+				values = append(values, "NULL")
+				continue
+			}
+			values = append(values, *value)
+		}
+		rows = append(rows, values)
+	}
+	return rows
+}
+
+func TestFetchPage(t *testing.T) {
+	dbURL := testDatabase(t)
+	tests := []struct {
+		name        string
+		offset      int
+		limit       int
+		wantRows    [][]string
+		wantHasMore bool
+	}{
+		{
+			name:        "first page",
+			limit:       2,
+			wantRows:    [][]string{{"1", "alice", "alice@example.com"}, {"2", "bob", "NULL"}},
+			wantHasMore: true,
+		},
+		{
+			name:     "last page",
+			offset:   2,
+			limit:    2,
+			wantRows: [][]string{{"3", "carol", "carol@example.com"}},
+		},
+		{
+			name:     "exactly full page",
+			limit:    3,
+			wantRows: [][]string{{"1", "alice", "alice@example.com"}, {"2", "bob", "NULL"}, {"3", "carol", "carol@example.com"}},
+		},
+		{
+			name:     "past the end",
+			offset:   5,
+			limit:    2,
+			wantRows: [][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := FetchPage(context.Background(), dbURL, "main", "users", []string{"id"}, tt.offset, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"id", "name", "email"}; !reflect.DeepEqual(page.Columns, want) {
+				t.Errorf("columns = %v, want %v", page.Columns, want)
+			}
+			if got := values(page); !reflect.DeepEqual(got, tt.wantRows) {
+				t.Errorf("rows = %v, want %v", got, tt.wantRows)
+			}
+			if page.HasMore != tt.wantHasMore || page.Offset != tt.offset {
+				t.Errorf("has more = %v, offset = %d, want %v, %d", page.HasMore, page.Offset, tt.wantHasMore, tt.offset)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	dbURL := testDatabase(t)
+	tests := []struct {
+		name        string
+		query       string
+		limit       int
+		wantRows    [][]string
+		wantHasMore bool
+		wantErr     bool
+	}{
+		{
+			name:     "nulls",
+			query:    "SELECT name, email FROM users WHERE id = 2;",
+			limit:    10,
+			wantRows: [][]string{{"bob", "NULL"}},
+		},
+		{
+			name:        "limit",
+			query:       "SELECT id FROM users ORDER BY id",
+			limit:       2,
+			wantRows:    [][]string{{"1"}, {"2"}},
+			wantHasMore: true,
+		},
+		{
+			name:     "within limit",
+			query:    "SELECT id FROM users ORDER BY id",
+			limit:    3,
+			wantRows: [][]string{{"1"}, {"2"}, {"3"}},
+		},
+		{
+			name:    "write",
+			query:   "DELETE FROM users",
+			limit:   10,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Query(context.Background(), dbURL, tt.query, tt.limit)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := values(page); !reflect.DeepEqual(got, tt.wantRows) || page.HasMore != tt.wantHasMore {
+				t.Errorf("rows = %v, has more = %v, want %v, %v", got, page.HasMore, tt.wantRows, tt.wantHasMore)
+			}
+		})
+	}
+}
+
+func TestQueryOnlyConnection(t *testing.T) {
+	conn, _, err := Open(testDatabase(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = conn.Exec("DELETE FROM users"); err == nil {
+		t.Error("expected the connection to refuse writes")
+	}
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// UnknownRows is the row count of tables the database keeps no estimate of
+const UnknownRows = -1
+
+// keyBatch is the number of random keys looked up per query, long IN lists make mysql give up on its index
+const keyBatch = 1000
+
+// Sampling is how the profiled rows were picked out of the table
+type Sampling string
+
+const (
+	// AllRows were read, the table has no more than the sample size
+	AllRows Sampling = "all"
+	// RandomRows were picked across the table
+	RandomRows Sampling = "random"
+	// FirstRows are the first rows the database returned, of tables that cannot be sampled cheaply at random
+	FirstRows Sampling = "first"
+)
+
+// integerTypes are the mysql types of primary keys that random keys can be picked of
+var integerTypes = []string{"tinyint", "smallint", "mediumint", "int", "integer", "bigint"}
+
+// sampler reads the values of a column without reading the whole table
+type sampler struct {
+	tx      *sql.Tx
+	dialect Dialect
+	schema  string
+	table   string
+	column  string
+}
+
+// Querier runs queries on a connection or within a transaction
+type Querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// EstimateRows is the number of rows of the table as estimated by the database, which is cheap to read, or UnknownRows.
+// On sqlite it is the span of the rowids, which overestimates tables that rows were deleted from.
+func EstimateRows(ctx context.Context, q Querier, dialect Dialect, schema, table string) int64 {
+	var query string
+	switch dialect {
+	case Postgres:
+		// reltuples is -1 on tables that were never analyzed
+		query = `SELECT c.reltuples::bigint FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2`
+	case MySQL:
+		query = `SELECT COALESCE(TABLE_ROWS, -1) FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ?`
+	default:
+		// fails on tables without rowid
+		var rows int64
+		span := fmt.Sprintf("SELECT COALESCE(MAX(rowid) - MIN(rowid) + 1, 0) FROM %s", dialect.Table(schema, table))
+		if err := q.QueryRowContext(ctx, span).Scan(&rows); err != nil || rows < 0 {
+			return UnknownRows
+		}
+		return rows
+	}
+	var rows int64
+	if err := q.QueryRowContext(ctx, query, schema, table).Scan(&rows); err != nil || rows < 0 {
+		return UnknownRows
+	}
+	return rows
+}
+
+// firstRows reads up to limit rows, which are all the rows of the table unless it has more
+func (s *sampler) firstRows(ctx context.Context, limit int) ([]*string, Sampling, error) {
+	// the limit keeps drivers from reading the rest of the table when the rows are closed
+	query := fmt.Sprintf("SELECT %s FROM %s LIMIT %d", s.dialect.Quote(s.column), s.dialect.Table(s.schema, s.table), limit+1)
+	page, err := s.query(ctx, query, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	if page.HasMore {
+		return columnValues(page), FirstRows, nil
+	}
+	return columnValues(page), AllRows, nil
+}
+
+// randomRows picks about sampleSize rows at random out of the estimated rows of the table.
+// Postgres samples pages of the table, mysql and sqlite look up random keys within the range of an integer key.
+// Tables of other keys are not sampled, and their first rows are read instead.
+func (s *sampler) randomRows(ctx context.Context, sampleSize int, estimate int64) ([]*string, Sampling, error) {
+	column, table := s.dialect.Quote(s.column), s.dialect.Table(s.schema, s.table)
+	if s.dialect == Postgres {
+		// pages are picked by the percentage, which is raised a bit so that enough rows are usually picked
+		percent := min(100, 110*float64(sampleSize)/float64(estimate))
+		query := fmt.Sprintf("SELECT %s FROM %s TABLESAMPLE SYSTEM (%s) LIMIT %d", column, table, strconv.FormatFloat(percent, 'f', -1, 64), sampleSize)
+		page, err := s.query(ctx, query, sampleSize)
+		if err != nil {
+			return nil, "", err
+		}
+		return columnValues(page), RandomRows, nil
+	}
+
+	key, ok := s.integerKey(ctx)
+	if !ok {
+		return s.firstRows(ctx, sampleSize)
+	}
+	low, high, ok := keyRange(ctx, s.tx, s.dialect, s.schema, s.table, key)
+	if !ok {
+		return s.firstRows(ctx, sampleSize)
+	}
+	var values []*string
+	keys := randomKeys(sampleSize, low, high)
+	for start := 0; start < len(keys); start += keyBatch {
+		batch := keys[start:min(start+keyBatch, len(keys))]
+		literals := make([]string, 0, len(batch))
+		for _, k := range batch {
+			literals = append(literals, strconv.FormatInt(k, 10))
+		}
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (%s)", column, table, key, strings.Join(literals, ", "))
+		page, err := s.query(ctx, query, len(batch))
+		if err != nil {
+			return nil, "", err
+		}
+		values = append(values, columnValues(page)...)
+	}
+	return values, RandomRows, nil
+}
+
+// integerKey is the quoted key column random keys are picked of: the rowid of sqlite tables, and the primary key of
+// mysql tables when it is a single integer column
+func (s *sampler) integerKey(ctx context.Context) (string, bool) {
+	if s.dialect == SQLite {
+		return "rowid", true
+	}
+	rows, err := s.tx.QueryContext(ctx, `SELECT COLUMN_NAME, DATA_TYPE FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ? AND COLUMN_KEY = 'PRI'`, s.schema, s.table)
+	if err != nil {
+		return "", false
+	}
+	defer rows.Close()
+	var names, types []string
+	for rows.Next() {
+		var name, typ string
+		if err = rows.Scan(&name, &typ); err != nil {
+			return "", false
+		}
+		names, types = append(names, name), append(types, strings.ToLower(typ))
+	}
+	if rows.Err() != nil || len(names) != 1 || !slices.Contains(integerTypes, types[0]) {
+		return "", false
+	}
+	return s.dialect.Quote(names[0]), true
+}
+
+// keyRange is the lowest and highest value of the quoted key column, both read off its index.
+// It fails on empty tables, on sqlite tables without rowid, and on ranges too wide to count.
+func keyRange(ctx context.Context, q Querier, dialect Dialect, schema, table, key string) (int64, int64, bool) {
+	var low, high sql.NullInt64
+	query := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", key, key, dialect.Table(schema, table))
+	if err := q.QueryRowContext(ctx, query).Scan(&low, &high); err != nil || !low.Valid || !high.Valid || high.Int64-low.Int64+1 <= 0 {
+		return 0, 0, false
+	}
+	return low.Int64, high.Int64, true
+}
+
+func (s *sampler) query(ctx context.Context, query string, limit int) (*Page, error) {
+	rows, err := s.tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return readPage(rows, limit)
+}
+
+// randomKeys picks n distinct keys within [low, high] at random, or all of them when there are no more than n.
+// Keys that no row has, e.g. of deleted rows, make for a smaller sample.
+func randomKeys(n int, low, high int64) []int64 {
+	span := high - low + 1
+	if span <= int64(n) {
+		keys := make([]int64, 0, span)
+		for k := low; k <= high; k++ {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+	picked := make(map[int64]bool, n)
+	for len(picked) < n {
+		picked[low+rand.Int64N(span)] = true
+	}
+	keys := make([]int64, 0, n)
+	for k := range picked {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// columnValues are the values of the single column of the page
+func columnValues(page *Page) []*string {
+	values := make([]*string, 0, len(page.Rows))
+	for _, row := range page.Rows {
+		values = append(values, row[0])
+	}
+	return values
+}
@@ -0,0 +1,69 @@
+package inspect
+
+import (
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// runAtlas runs the atlas cli directly, for when atlasexec does not expose what we need (e.g. streaming stderr).
+// It mirrors the way atlasexec runs commands.
+func runAtlas(ctx context.Context, cliPath string, args []string, stderr io.Writer) ([]byte, error) {
+	var stdout, errBuf bytes.Buffer
+	cmd := exec.CommandContext(ctx, cliPath, args...)
+	cmd.Env = append(os.Environ(), "ATLAS_NO_UPDATE_NOTIFIER=1")
+	// do not wait on lingering child processes holding the pipes once cancelled
+	cmd.WaitDelay = time.Second
+	cmd.Stdout = &stdout
+	cmd.Stderr = &errBuf
+	if stderr != nil {
+		cmd.Stderr = io.MultiWriter(&errBuf, stderr)
+	}
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		msg := strings.TrimSpace(errBuf.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg == "" {
+			return nil, err
+		}
+		// avoid printing cobra error prefix twice...
+		return nil, errors.New(strings.TrimPrefix(msg, cliErrorPrefix))
+	}
+	return stdout.Bytes(), nil
+}
+
+func schemaInspectArgs(params *atlasexec.SchemaInspectParams) []string {
+	args := []string{"schema", "inspect"}
+	if params.Env != "" {
+		args = append(args, "--env", params.Env)
+	}
+	if params.ConfigURL != "" {
+		args = append(args, "--config", params.ConfigURL)
+	}
+	if params.URL != "" {
+		args = append(args, "--url", params.URL)
+	}
+	if params.DevURL != "" {
+		args = append(args, "--dev-url", params.DevURL)
+	}
+	if params.Format != "" {
+		args = append(args, "--format", params.Format)
+	}
+	if len(params.Schema) > 0 {
+		args = append(args, "--schema", strings.Join(params.Schema, ","))
+	}
+	if len(params.Exclude) > 0 {
+		args = append(args, "--exclude", strings.Join(params.Exclude, ","))
+	}
+	return append(args, params.Vars.AsArgs()...)
+}
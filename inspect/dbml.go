@@ -0,0 +1,264 @@
+package inspect
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/samber/lo"
+	"strconv"
+	"strings"
+)
+
+// DBML reference: https://dbml.dbdiagram.io/docs
+// The mapping is lossy in both directions: DBML has no notion of charsets, collations or index sort order,
+// and atlas has no notion of DBML enums, table groups or relationship cardinality.
+
+const dbmlIndent = "  "
+
+// ToDBML writes the data as dbml, failing on names dbml cannot quote
+func ToDBML(data Data) (string, error) {
+	if err := checkDBMLNames(data); err != nil {
+		return "", err
+	}
+	sb := strings.Builder{}
+	for _, schema := range data.Schemas {
+		for _, table := range schema.Tables {
+			if sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			writeDBMLTable(&sb, schema, table)
+		}
+	}
+	for _, schema := range data.Schemas {
+		for _, table := range schema.Tables {
+			for _, fk := range table.ForeignKeys {
+				sb.WriteString("\n")
+				writeDBMLRef(&sb, schema, table, fk)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+// checkDBMLNames fails on names containing double quotes, quoted dbml names have no escape sequences
+func checkDBMLNames(data Data) error {
+	check := func(kind, name string) error {
+		if strings.Contains(name, `"`) {
+			return fmt.Errorf("%s '%s' cannot be written as dbml, which does not allow double quotes in names", kind, name)
+		}
+		return nil
+	}
+	for _, schema := range data.Schemas {
+		if err := check("schema", schema.Name); err != nil {
+			return err
+		}
+		for _, table := range schema.Tables {
+			if err := check("table", table.Name); err != nil {
+				return err
+			}
+			for _, col := range table.Columns {
+				if err := cmp.Or(check("column", col.Name), check("type", col.Type)); err != nil {
+					return err
+				}
+			}
+			for _, fk := range table.ForeignKeys {
+				// referenced tables may be left out of the data, their names are checked along with the reference
+				if err := cmp.Or(check("foreign key", fk.Name), check("table", fk.References.Table)); err != nil {
+					return err
+				}
+				for _, col := range fk.References.Columns {
+					if err := check("column", col); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func writeDBMLTable(sb *strings.Builder, schema Schema, table Table) {
+	sb.WriteString("Table ")
+	sb.WriteString(dbmlTableName(schema.Name, table.Name))
+	sb.WriteString(" {\n")
+
+	singlePK := ""
+	if table.PrimaryKey != nil && len(table.PrimaryKey.Parts) == 1 && table.PrimaryKey.Parts[0].Column != "" {
+		singlePK = table.PrimaryKey.Parts[0].Column
+	}
+	for _, col := range table.Columns {
+		sb.WriteString(dbmlIndent)
+		sb.WriteString(dbmlIdent(col.Name))
+		sb.WriteString(" ")
+		sb.WriteString(dbmlType(col.Type))
+		var settings []string
+		if col.Name == singlePK {
+			settings = append(settings, "pk")
+		}
+		if !col.Null {
+			settings = append(settings, "not null")
+		}
+		if col.Default != "" {
+			settings = append(settings, "default: "+dbmlDefault(col.Default))
+		}
+		if col.Comment != "" {
+			settings = append(settings, "note: "+dbmlString(col.Comment))
+		}
+		writeDBMLSettings(sb, settings)
+		sb.WriteString("\n")
+	}
+
+	indexes := make([]string, 0, len(table.Indexes)+1)
+	if table.PrimaryKey != nil && singlePK == "" && len(table.PrimaryKey.Parts) > 0 {
+		indexes = append(indexes, dbmlIndex(*table.PrimaryKey, true))
+	}
+	for _, idx := range table.Indexes {
+		indexes = append(indexes, dbmlIndex(idx, false))
+	}
+	if len(indexes) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(dbmlIndent)
+		sb.WriteString("indexes {\n")
+		for _, idx := range indexes {
+			sb.WriteString(dbmlIndent + dbmlIndent)
+			sb.WriteString(idx)
+			sb.WriteString("\n")
+		}
+		sb.WriteString(dbmlIndent)
+		sb.WriteString("}\n")
+	}
+
+	if table.Comment != "" {
+		sb.WriteString("\n")
+		sb.WriteString(dbmlIndent)
+		sb.WriteString("Note: ")
+		sb.WriteString(dbmlString(table.Comment))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}\n")
+}
+
+func dbmlIndex(idx Index, pk bool) string {
+	parts := make([]string, 0, len(idx.Parts))
+	for _, part := range idx.Parts {
+		if part.Expr != "" {
+			parts = append(parts, "`"+strings.ReplaceAll(part.Expr, "`", "'")+"`")
+		} else {
+			parts = append(parts, dbmlIdent(part.Column))
+		}
+	}
+	sb := strings.Builder{}
+	if len(parts) == 1 {
+		sb.WriteString(parts[0])
+	} else {
+		sb.WriteString("(" + strings.Join(parts, ", ") + ")")
+	}
+	var settings []string
+	if pk {
+		settings = append(settings, "pk")
+	} else if idx.Unique {
+		settings = append(settings, "unique")
+	}
+	if idx.Name != "" {
+		settings = append(settings, "name: "+dbmlString(idx.Name))
+	}
+	writeDBMLSettings(&sb, settings)
+	return sb.String()
+}
+
+func writeDBMLRef(sb *strings.Builder, schema Schema, table Table, fk ForeignKey) {
+	sb.WriteString("Ref")
+	if fk.Name != "" {
+		sb.WriteString(" ")
+		sb.WriteString(dbmlIdent(fk.Name))
+	}
+	sb.WriteString(": ")
+	sb.WriteString(dbmlEndpoint(schema.Name, table.Name, fk.Columns))
+	sb.WriteString(" > ")
+	sb.WriteString(dbmlEndpoint(schema.Name, fk.References.Table, fk.References.Columns))
+	sb.WriteString("\n")
+}
+
+func writeDBMLSettings(sb *strings.Builder, settings []string) {
+	if len(settings) == 0 {
+		return
+	}
+	sb.WriteString(" [")
+	sb.WriteString(strings.Join(settings, ", "))
+	sb.WriteString("]")
+}
+
+func dbmlTableName(schema, table string) string {
+	if schema == "" {
+		return dbmlIdent(table)
+	}
+	return dbmlIdent(schema) + "." + dbmlIdent(table)
+}
+
+func dbmlEndpoint(schema, table string, columns []string) string {
+	quoted := make([]string, 0, len(columns))
+	for _, col := range columns {
+		quoted = append(quoted, dbmlIdent(col))
+	}
+	if len(quoted) == 1 {
+		return dbmlTableName(schema, table) + "." + quoted[0]
+	}
+	return dbmlTableName(schema, table) + ".(" + strings.Join(quoted, ", ") + ")"
+}
+
+// dbmlDefault writes sql quoted strings as dbml strings, keeps literals and wraps anything else as an expression
+func dbmlDefault(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		return dbmlString(strings.ReplaceAll(value[1:len(value)-1], "''", "'"))
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil || lo.Contains([]string{"true", "false", "null"}, strings.ToLower(value)) {
+		return value
+	}
+	return "`" + value + "`"
+}
+
+func dbmlIdent(name string) string {
+	if isDBMLPlainIdent(name) {
+		return name
+	}
+	return `"` + name + `"`
+}
+
+func dbmlType(typ string) string {
+	if typ == "" {
+		return "unknown"
+	}
+	// types such as varchar(255) or decimal(10,2) can be written as is, anything else with spaces must be quoted
+	open := strings.IndexByte(typ, '(')
+	if open > 0 && strings.HasSuffix(typ, ")") && isDBMLPlainIdent(typ[:open]) {
+		return typ
+	}
+	return dbmlIdent(typ)
+}
+
+func dbmlString(s string) string {
+	if strings.Contains(s, "\n") {
+		// every quote is escaped, as a quote ending the text would otherwise end the string early
+		return "'''" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", `\'`) + "'''"
+	}
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", `\'`) + "'"
+}
+
+func isDBMLPlainIdent(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !isDBMLIdentRune(r) || (i == 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDBMLIdentRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func dbmlError(line int, format string, args ...any) error {
+	return fmt.Errorf("failed to parse dbml, line %d: %s", line, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,760 @@
+package inspect
+
+import (
+	"fmt"
+	"github.com/samber/lo"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const dbmlDefaultSchema = "public"
+
+type dbmlTokenKind int
+
+const (
+	dbmlTokEOF dbmlTokenKind = iota
+	dbmlTokWord
+	dbmlTokQuoted
+	dbmlTokString
+	dbmlTokExpr
+	dbmlTokPunct
+)
+
+type dbmlToken struct {
+	kind dbmlTokenKind
+	text string
+	line int
+}
+
+type dbmlSetting struct {
+	key   string
+	value []dbmlToken
+}
+
+type dbmlRefEndpoint struct {
+	schema  string
+	table   string
+	columns []string
+	line    int
+}
+
+type dbmlRef struct {
+	name string
+	from dbmlRefEndpoint
+	op   string
+	to   dbmlRefEndpoint
+	line int
+}
+
+type dbmlTable struct {
+	schema string
+	alias  string
+	table  Table
+}
+
+type dbmlParser struct {
+	tokens []dbmlToken
+	pos    int
+	tables []*dbmlTable
+	refs   []dbmlRef
+}
+
+// dbmlElements are the keywords starting the top level elements of dbml
+var dbmlElements = []string{"table", "ref", "enum", "tablegroup", "project", "note", "tablepartial", "records"}
+
+// isDBML tells whether the content starts with a dbml element, after any whitespace and comments
+func isDBML(raw []byte) bool {
+	src := string(raw)
+	for {
+		src = strings.TrimLeftFunc(src, unicode.IsSpace)
+		switch {
+		case strings.HasPrefix(src, "//"):
+			_, src, _ = strings.Cut(src, "\n")
+		case strings.HasPrefix(src, "/*"):
+			_, src, _ = strings.Cut(src, "*/")
+		default:
+			word := src[:len(src)-len(strings.TrimLeftFunc(src, unicode.IsLetter))]
+			return lo.Contains(dbmlElements, strings.ToLower(word))
+		}
+	}
+}
+
+func parseDBML(raw []byte) (*Data, error) {
+	tokens, err := tokenizeDBML(string(raw))
+	if err != nil {
+		return nil, err
+	}
+	p := &dbmlParser{tokens: tokens}
+	for p.peek().kind != dbmlTokEOF {
+		tok := p.next()
+		if tok.kind != dbmlTokWord {
+			return nil, dbmlError(tok.line, "unexpected '%s'", tok.text)
+		}
+		switch strings.ToLower(tok.text) {
+		case "table":
+			err = p.parseTable()
+		case "ref":
+			err = p.parseRef()
+		case "enum", "tablegroup", "project", "note", "tablepartial", "records":
+			err = p.skipBlock()
+		default:
+			err = dbmlError(tok.line, "unsupported element '%s'", tok.text)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = p.resolveRefs(); err != nil {
+		return nil, err
+	}
+	return p.data(), nil
+}
+
+func (p *dbmlParser) data() *Data {
+	data := &Data{}
+	schemaIdx := make(map[string]int)
+	for _, t := range p.tables {
+		idx, ok := schemaIdx[t.schema]
+		if !ok {
+			idx = len(data.Schemas)
+			schemaIdx[t.schema] = idx
+			data.Schemas = append(data.Schemas, Schema{Name: t.schema})
+		}
+		data.Schemas[idx].Tables = append(data.Schemas[idx].Tables, t.table)
+	}
+	return data
+}
+
+func (p *dbmlParser) parseTable() error {
+	nameTok := p.peek()
+	names, err := p.parseQualifiedName()
+	if err != nil {
+		return err
+	}
+	if len(names) > 2 {
+		return dbmlError(nameTok.line, "invalid table name '%s'", strings.Join(names, "."))
+	}
+	t := &dbmlTable{schema: dbmlDefaultSchema}
+	if len(names) == 2 {
+		t.schema = names[0]
+	}
+	t.table.Name = names[len(names)-1]
+	if p.peek().kind == dbmlTokWord && strings.EqualFold(p.peek().text, "as") {
+		p.next()
+		alias := p.next()
+		if alias.kind != dbmlTokWord && alias.kind != dbmlTokQuoted {
+			return dbmlError(alias.line, "expected table alias")
+		}
+		t.alias = alias.text
+	}
+	if p.peekPunct("[") {
+		settings, err := p.parseSettings()
+		if err != nil {
+			return err
+		}
+		for _, s := range settings {
+			if s.key == "note" {
+				t.table.Comment = settingText(s)
+			}
+		}
+	}
+	if err = p.expectPunct("{"); err != nil {
+		return err
+	}
+	for !p.peekPunct("}") {
+		tok := p.peek()
+		switch {
+		case tok.kind == dbmlTokEOF:
+			return dbmlError(tok.line, "unterminated table '%s'", t.table.Name)
+		case tok.kind == dbmlTokWord && strings.EqualFold(tok.text, "indexes") && p.peekPunctAt(1, "{"):
+			p.next()
+			if err = p.parseIndexes(t); err != nil {
+				return err
+			}
+		case tok.kind == dbmlTokWord && strings.EqualFold(tok.text, "note") && (p.peekPunctAt(1, ":") || p.peekPunctAt(1, "{")):
+			p.next()
+			if t.table.Comment, err = p.parseNote(); err != nil {
+				return err
+			}
+		default:
+			if err = p.parseColumn(t); err != nil {
+				return err
+			}
+		}
+	}
+	p.next()
+	p.tables = append(p.tables, t)
+	return nil
+}
+
+func (p *dbmlParser) parseColumn(t *dbmlTable) error {
+	nameTok := p.next()
+	if nameTok.kind != dbmlTokWord && nameTok.kind != dbmlTokQuoted {
+		return dbmlError(nameTok.line, "expected column name, got '%s'", nameTok.text)
+	}
+	col := Column{Name: nameTok.text, Null: true}
+	typ, err := p.parseType(nameTok.line)
+	if err != nil {
+		return err
+	}
+	col.Type = typ
+	if p.peekPunct("[") && p.peek().line == nameTok.line {
+		settings, err := p.parseSettings()
+		if err != nil {
+			return err
+		}
+		for _, s := range settings {
+			switch s.key {
+			case "pk", "primary key":
+				col.Null = false
+				t.table.PrimaryKey = &Index{Parts: []IndexPart{{Column: col.Name}}}
+			case "not null":
+				col.Null = false
+			case "null":
+				col.Null = true
+			case "unique":
+				t.table.Indexes = append(t.table.Indexes, Index{
+					Name:   fmt.Sprintf("%s_%s_key", t.table.Name, col.Name),
+					Unique: true,
+					Parts:  []IndexPart{{Column: col.Name}},
+				})
+			case "note":
+				col.Comment = settingText(s)
+			case "default":
+				col.Default = defaultText(s)
+			case "ref":
+				if err = p.addInlineRef(t, col.Name, s); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	t.table.Columns = append(t.table.Columns, col)
+	return nil
+}
+
+func (p *dbmlParser) parseType(line int) (string, error) {
+	tok := p.peek()
+	if tok.line != line || (tok.kind != dbmlTokWord && tok.kind != dbmlTokQuoted) {
+		return "", dbmlError(line, "expected column type")
+	}
+	p.next()
+	sb := strings.Builder{}
+	sb.WriteString(tok.text)
+	for p.peekPunct(".") && p.peek().line == line {
+		p.next()
+		sb.WriteString(".")
+		sb.WriteString(p.next().text)
+	}
+	if p.peekPunct("(") && p.peek().line == line {
+		p.next()
+		sb.WriteString("(")
+		for !p.peekPunct(")") {
+			arg := p.next()
+			switch arg.kind {
+			case dbmlTokEOF:
+				return "", dbmlError(line, "unterminated column type")
+			case dbmlTokString:
+				// type arguments such as enum values are sql literals, their quotes are part of the type
+				sb.WriteString("'" + strings.ReplaceAll(arg.text, "'", "''") + "'")
+			case dbmlTokQuoted:
+				sb.WriteString(`"` + arg.text + `"`)
+			default:
+				sb.WriteString(arg.text)
+			}
+		}
+		p.next()
+		sb.WriteString(")")
+	}
+	if p.peekPunct("[") && p.peekPunctAt(1, "]") && p.peek().line == line {
+		p.next()
+		p.next()
+		sb.WriteString("[]")
+	}
+	return sb.String(), nil
+}
+
+func (p *dbmlParser) parseIndexes(t *dbmlTable) error {
+	if err := p.expectPunct("{"); err != nil {
+		return err
+	}
+	for !p.peekPunct("}") {
+		tok := p.next()
+		var parts []IndexPart
+		switch {
+		case tok.kind == dbmlTokEOF:
+			return dbmlError(tok.line, "unterminated indexes of table '%s'", t.table.Name)
+		case tok.kind == dbmlTokPunct && tok.text == "(":
+			for !p.peekPunct(")") {
+				part := p.next()
+				switch part.kind {
+				case dbmlTokWord, dbmlTokQuoted:
+					parts = append(parts, IndexPart{Column: part.text})
+				case dbmlTokExpr:
+					parts = append(parts, IndexPart{Expr: part.text})
+				case dbmlTokEOF:
+					return dbmlError(tok.line, "unterminated index definition")
+				}
+			}
+			p.next()
+		case tok.kind == dbmlTokWord || tok.kind == dbmlTokQuoted:
+			parts = []IndexPart{{Column: tok.text}}
+		case tok.kind == dbmlTokExpr:
+			parts = []IndexPart{{Expr: tok.text}}
+		default:
+			return dbmlError(tok.line, "unexpected '%s' in indexes", tok.text)
+		}
+		idx := Index{Parts: parts}
+		pk := false
+		if p.peekPunct("[") {
+			settings, err := p.parseSettings()
+			if err != nil {
+				return err
+			}
+			for _, s := range settings {
+				switch s.key {
+				case "pk", "primary key":
+					pk = true
+				case "unique":
+					idx.Unique = true
+				case "name":
+					idx.Name = settingText(s)
+				}
+			}
+		}
+		if pk {
+			t.table.PrimaryKey = &idx
+			for i, col := range t.table.Columns {
+				for _, part := range parts {
+					if part.Column == col.Name {
+						t.table.Columns[i].Null = false
+					}
+				}
+			}
+		} else {
+			t.table.Indexes = append(t.table.Indexes, idx)
+		}
+	}
+	p.next()
+	return nil
+}
+
+func (p *dbmlParser) parseNote() (string, error) {
+	if p.peekPunct(":") {
+		p.next()
+		tok := p.next()
+		if tok.kind != dbmlTokString {
+			return "", dbmlError(tok.line, "expected note string")
+		}
+		return tok.text, nil
+	}
+	if err := p.expectPunct("{"); err != nil {
+		return "", err
+	}
+	tok := p.next()
+	if tok.kind != dbmlTokString {
+		return "", dbmlError(tok.line, "expected note string")
+	}
+	return tok.text, p.expectPunct("}")
+}
+
+func (p *dbmlParser) parseRef() error {
+	name := ""
+	if tok := p.peek(); tok.kind == dbmlTokWord || tok.kind == dbmlTokQuoted {
+		name = p.next().text
+	}
+	if p.peekPunct(":") {
+		p.next()
+		return p.parseRefBody(name)
+	}
+	if err := p.expectPunct("{"); err != nil {
+		return err
+	}
+	for !p.peekPunct("}") {
+		if p.peek().kind == dbmlTokEOF {
+			return dbmlError(p.peek().line, "unterminated ref")
+		}
+		if err := p.parseRefBody(name); err != nil {
+			return err
+		}
+	}
+	p.next()
+	return nil
+}
+
+func (p *dbmlParser) parseRefBody(name string) error {
+	from, err := p.parseEndpoint()
+	if err != nil {
+		return err
+	}
+	op := p.next()
+	if op.kind != dbmlTokPunct || !lo.Contains([]string{">", "<", "-", "<>"}, op.text) {
+		return dbmlError(op.line, "expected relationship, got '%s'", op.text)
+	}
+	to, err := p.parseEndpoint()
+	if err != nil {
+		return err
+	}
+	if p.peekPunct("[") && p.peek().line == to.line {
+		if _, err = p.parseSettings(); err != nil {
+			return err
+		}
+	}
+	p.refs = append(p.refs, dbmlRef{name: name, from: from, op: op.text, to: to, line: op.line})
+	return nil
+}
+
+func (p *dbmlParser) addInlineRef(t *dbmlTable, column string, s dbmlSetting) error {
+	if len(s.value) < 2 || s.value[0].kind != dbmlTokPunct {
+		return dbmlError(p.tokens[p.pos-1].line, "invalid inline ref")
+	}
+	sub := &dbmlParser{tokens: append(append([]dbmlToken{}, s.value[1:]...), dbmlToken{kind: dbmlTokEOF, line: s.value[0].line})}
+	to, err := sub.parseEndpoint()
+	if err != nil {
+		return err
+	}
+	from := dbmlRefEndpoint{schema: t.schema, table: t.table.Name, columns: []string{column}, line: s.value[0].line}
+	p.refs = append(p.refs, dbmlRef{from: from, op: s.value[0].text, to: to, line: from.line})
+	return nil
+}
+
+func (p *dbmlParser) parseEndpoint() (dbmlRefEndpoint, error) {
+	ep := dbmlRefEndpoint{line: p.peek().line}
+	var names []string
+	for {
+		if p.peekPunct("(") {
+			p.next()
+			for !p.peekPunct(")") {
+				tok := p.next()
+				switch tok.kind {
+				case dbmlTokWord, dbmlTokQuoted:
+					ep.columns = append(ep.columns, tok.text)
+				case dbmlTokEOF:
+					return ep, dbmlError(ep.line, "unterminated ref columns")
+				}
+			}
+			p.next()
+			break
+		}
+		tok := p.next()
+		if tok.kind != dbmlTokWord && tok.kind != dbmlTokQuoted {
+			return ep, dbmlError(tok.line, "expected ref endpoint, got '%s'", tok.text)
+		}
+		names = append(names, tok.text)
+		if !p.peekPunct(".") {
+			break
+		}
+		p.next()
+	}
+	if ep.columns == nil {
+		if len(names) < 2 {
+			return ep, dbmlError(ep.line, "ref endpoint must specify table and column")
+		}
+		ep.columns = []string{names[len(names)-1]}
+		names = names[:len(names)-1]
+	}
+	switch len(names) {
+	case 1:
+		ep.table = names[0]
+	case 2:
+		ep.schema, ep.table = names[0], names[1]
+	default:
+		return ep, dbmlError(ep.line, "invalid ref endpoint")
+	}
+	return ep, nil
+}
+
+func (p *dbmlParser) resolveRefs() error {
+	for _, ref := range p.refs {
+		holder, target := ref.from, ref.to
+		switch ref.op {
+		case "<":
+			holder, target = ref.to, ref.from
+		case "<>":
+			// many-to-many relationships have no foreign key representation
+			continue
+		}
+		holderTable := p.lookupTable(holder)
+		if holderTable == nil {
+			return dbmlError(ref.line, "ref to unknown table '%s'", holder.table)
+		}
+		targetTable := p.lookupTable(target)
+		if targetTable == nil {
+			return dbmlError(ref.line, "ref to unknown table '%s'", target.table)
+		}
+		name := ref.name
+		if name == "" {
+			name = fmt.Sprintf("%s_%s_fkey", holderTable.table.Name, strings.Join(holder.columns, "_"))
+		}
+		holderTable.table.ForeignKeys = append(holderTable.table.ForeignKeys, ForeignKey{
+			Name:    name,
+			Columns: holder.columns,
+			References: ForeignKeyReferences{
+				Table:   targetTable.table.Name,
+				Columns: target.columns,
+			},
+		})
+	}
+	return nil
+}
+
+func (p *dbmlParser) lookupTable(ep dbmlRefEndpoint) *dbmlTable {
+	for _, t := range p.tables {
+		if ep.schema == "" && t.alias != "" && t.alias == ep.table {
+			return t
+		}
+	}
+	for _, t := range p.tables {
+		if t.table.Name == ep.table && (ep.schema == "" || ep.schema == t.schema) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (p *dbmlParser) parseSettings() ([]dbmlSetting, error) {
+	open := p.next()
+	var settings []dbmlSetting
+	var curr []dbmlToken
+	flush := func() {
+		if len(curr) == 0 {
+			return
+		}
+		s := dbmlSetting{}
+		keyEnd := len(curr)
+		for i, tok := range curr {
+			if tok.kind == dbmlTokPunct && tok.text == ":" {
+				keyEnd = i
+				s.value = curr[i+1:]
+				break
+			}
+		}
+		keys := make([]string, 0, keyEnd)
+		for _, tok := range curr[:keyEnd] {
+			keys = append(keys, strings.ToLower(tok.text))
+		}
+		s.key = strings.Join(keys, " ")
+		settings = append(settings, s)
+		curr = nil
+	}
+	for {
+		tok := p.next()
+		switch {
+		case tok.kind == dbmlTokEOF:
+			return nil, dbmlError(open.line, "unterminated settings")
+		case tok.kind == dbmlTokPunct && tok.text == "]":
+			flush()
+			return settings, nil
+		case tok.kind == dbmlTokPunct && tok.text == ",":
+			flush()
+		default:
+			curr = append(curr, tok)
+		}
+	}
+}
+
+func (p *dbmlParser) parseQualifiedName() ([]string, error) {
+	var names []string
+	for {
+		tok := p.next()
+		if tok.kind != dbmlTokWord && tok.kind != dbmlTokQuoted {
+			return nil, dbmlError(tok.line, "expected name, got '%s'", tok.text)
+		}
+		names = append(names, tok.text)
+		if !p.peekPunct(".") {
+			return names, nil
+		}
+		p.next()
+	}
+}
+
+func (p *dbmlParser) skipBlock() error {
+	start := p.peek().line
+	for !p.peekPunct("{") {
+		tok := p.next()
+		if tok.kind == dbmlTokEOF {
+			return dbmlError(start, "unterminated block")
+		}
+		if tok.kind == dbmlTokString && p.tokens[p.pos-2].text == ":" {
+			// single line form, e.g. `Note: '...'`
+			return nil
+		}
+	}
+	depth := 0
+	for {
+		tok := p.next()
+		switch {
+		case tok.kind == dbmlTokEOF:
+			return dbmlError(start, "unterminated block")
+		case tok.kind == dbmlTokPunct && tok.text == "{":
+			depth++
+		case tok.kind == dbmlTokPunct && tok.text == "}":
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+func (p *dbmlParser) expectPunct(punct string) error {
+	tok := p.next()
+	if tok.kind != dbmlTokPunct || tok.text != punct {
+		return dbmlError(tok.line, "expected '%s', got '%s'", punct, tok.text)
+	}
+	return nil
+}
+
+func (p *dbmlParser) peek() dbmlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *dbmlParser) peekPunct(punct string) bool {
+	return p.peekPunctAt(0, punct)
+}
+
+func (p *dbmlParser) peekPunctAt(offset int, punct string) bool {
+	if p.pos+offset >= len(p.tokens) {
+		return false
+	}
+	tok := p.tokens[p.pos+offset]
+	return tok.kind == dbmlTokPunct && tok.text == punct
+}
+
+func (p *dbmlParser) next() dbmlToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != dbmlTokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func settingText(s dbmlSetting) string {
+	texts := make([]string, 0, len(s.value))
+	for _, tok := range s.value {
+		texts = append(texts, tok.text)
+	}
+	return strings.Join(texts, " ")
+}
+
+// defaultText keeps string defaults quoted as in sql, to tell them from expressions, e.g. 'now()' from `now()`
+func defaultText(s dbmlSetting) string {
+	if len(s.value) == 1 && s.value[0].kind == dbmlTokString {
+		return "'" + strings.ReplaceAll(s.value[0].text, "'", "''") + "'"
+	}
+	// numbers are split into tokens at signs and dots, e.g. -1.5
+	number := strings.Join(lo.Map(s.value, func(tok dbmlToken, _ int) string { return tok.text }), "")
+	if _, err := strconv.ParseFloat(number, 64); err == nil && lo.EveryBy(s.value, func(tok dbmlToken) bool { return tok.kind == dbmlTokWord || tok.kind == dbmlTokPunct }) {
+		return number
+	}
+	return settingText(s)
+}
+
+func tokenizeDBML(src string) ([]dbmlToken, error) {
+	var tokens []dbmlToken
+	runes := []rune(src)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := line
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			if i+1 >= len(runes) {
+				return nil, dbmlError(start, "unterminated comment")
+			}
+			i += 2
+		case r == '\'' && i+2 < len(runes) && runes[i+1] == '\'' && runes[i+2] == '\'':
+			start := line
+			sb := strings.Builder{}
+			i += 3
+			for ; i < len(runes) && !(i+2 < len(runes) && runes[i] == '\'' && runes[i+1] == '\'' && runes[i+2] == '\''); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, dbmlError(start, "unterminated string")
+			}
+			i += 3
+			tokens = append(tokens, dbmlToken{kind: dbmlTokString, text: trimMultilineString(sb.String()), line: start})
+		case r == '\'' || r == '"' || r == '`':
+			start := line
+			sb := strings.Builder{}
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && r != '`' {
+					i++
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, dbmlError(start, "unterminated string")
+			}
+			i++
+			kind := map[rune]dbmlTokenKind{'\'': dbmlTokString, '"': dbmlTokQuoted, '`': dbmlTokExpr}[r]
+			tokens = append(tokens, dbmlToken{kind: kind, text: sb.String(), line: start})
+		case r == '<' && i+1 < len(runes) && runes[i+1] == '>':
+			tokens = append(tokens, dbmlToken{kind: dbmlTokPunct, text: "<>", line: line})
+			i += 2
+		case strings.ContainsRune("{}[]():,.<>-~", r):
+			tokens = append(tokens, dbmlToken{kind: dbmlTokPunct, text: string(r), line: line})
+			i++
+		case isDBMLIdentRune(r) || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (isDBMLIdentRune(runes[i]) || unicode.IsLetter(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, dbmlToken{kind: dbmlTokWord, text: string(runes[start:i]), line: line})
+		default:
+			tokens = append(tokens, dbmlToken{kind: dbmlTokPunct, text: string(r), line: line})
+			i++
+		}
+	}
+	return append(tokens, dbmlToken{kind: dbmlTokEOF, text: "end of file", line: line}), nil
+}
+
+// trimMultilineString drops the leading newline and common indentation of a triple-quoted string, as dbdiagram.io does
+func trimMultilineString(s string) string {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "\r"), "\n")
+	lines := strings.Split(strings.TrimRight(s, " \t\r\n"), "\n")
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			lines[i] = l[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,153 @@
+package inspect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDBMLRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		data Data
+	}{
+		{
+			name: "columns",
+			data: Data{Schemas: []Schema{{Name: "public", Tables: []Table{{
+				Name: "users",
+				Columns: []Column{
+					{Name: "id", Type: "int"},
+					{Name: "email", Type: "varchar(255)", Attrs: Attrs{Comment: "login, it's unique"}},
+					{Name: "bio", Type: "text", Null: true, Attrs: Attrs{Comment: "first line\nsecond line"}},
+					{Name: "role", Type: "enum('admin','it''s me')", Attrs: Attrs{Comment: "first line\nends with 'quotes'"}},
+				},
+				PrimaryKey: &Index{Parts: []IndexPart{{Column: "id"}}},
+				Attrs:      Attrs{Comment: "registered users"},
+			}}}}},
+		},
+		{
+			name: "defaults",
+			data: Data{Schemas: []Schema{{Name: "public", Tables: []Table{{
+				Name: "prices",
+				Columns: []Column{
+					{Name: "amount", Type: "decimal(10,2)", Default: "1.5"},
+					{Name: "delta", Type: "int", Default: "-1"},
+					{Name: "ratio", Type: "float", Default: "-2.5e-3"},
+					{Name: "currency", Type: "text", Default: "'usd'"},
+					{Name: "label", Type: "text", Default: "'it''s'"},
+					{Name: "active", Type: "boolean", Default: "true"},
+					{Name: "created_at", Type: "timestamp", Default: "now()"},
+				},
+			}}}}},
+		},
+		{
+			name: "indexes",
+			data: Data{Schemas: []Schema{{Name: "public", Tables: []Table{{
+				Name: "memberships",
+				Columns: []Column{
+					{Name: "user_id", Type: "int"},
+					{Name: "group_id", Type: "int"},
+					{Name: "role", Type: "text"},
+				},
+				PrimaryKey: &Index{Parts: []IndexPart{{Column: "user_id"}, {Column: "group_id"}}},
+				Indexes: []Index{
+					{Name: "memberships_role", Parts: []IndexPart{{Column: "role"}}},
+					{Name: "memberships_group_role", Unique: true, Parts: []IndexPart{{Column: "group_id"}, {Column: "role"}}},
+					{Name: "memberships_lower_role", Parts: []IndexPart{{Expr: "lower(role)"}}},
+				},
+			}}}}},
+		},
+		{
+			name: "foreign keys",
+			data: Data{Schemas: []Schema{{Name: "public", Tables: []Table{
+				{
+					Name:       "groups",
+					Columns:    []Column{{Name: "id", Type: "int"}, {Name: "parent_id", Type: "int", Null: true}},
+					PrimaryKey: &Index{Parts: []IndexPart{{Column: "id"}}},
+					ForeignKeys: []ForeignKey{{
+						Name:       "groups_parent",
+						Columns:    []string{"parent_id"},
+						References: ForeignKeyReferences{Table: "groups", Columns: []string{"id"}},
+					}},
+				},
+				{
+					Name:    "members",
+					Columns: []Column{{Name: "group_id", Type: "int"}},
+					ForeignKeys: []ForeignKey{{
+						Name:       "members_group",
+						Columns:    []string{"group_id"},
+						References: ForeignKeyReferences{Table: "groups", Columns: []string{"id"}},
+					}},
+				},
+			}}}},
+		},
+		{
+			name: "quoted names",
+			data: Data{Schemas: []Schema{{Name: "sales data", Tables: []Table{{
+				Name:       "order items",
+				Columns:    []Column{{Name: "unit price", Type: "double precision"}, {Name: "table", Type: "int"}},
+				PrimaryKey: &Index{Parts: []IndexPart{{Column: "unit price"}, {Column: "table"}}},
+			}}}}},
+		},
+		{
+			name: "schemas",
+			data: Data{Schemas: []Schema{
+				{Name: "public", Tables: []Table{{Name: "users", Columns: []Column{{Name: "id", Type: "int"}}}}},
+				{Name: "audit", Tables: []Table{{Name: "events", Columns: []Column{{Name: "user_id", Type: "int"}}}}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbml, err := ToDBML(tt.data)
+			if err != nil {
+				t.Fatalf("ToDBML: %v", err)
+			}
+			decoded, err := Decode([]byte(dbml), FormatDBML)
+			if err != nil {
+				t.Fatalf("Decode: %v\n%s", err, dbml)
+			}
+			if !reflect.DeepEqual(*decoded, tt.data) {
+				t.Errorf("round trip mismatch\ngot:  %+v\nwant: %+v\ndbml:\n%s", *decoded, tt.data, dbml)
+			}
+		})
+	}
+}
+
+func TestToDBMLRejectsDoubleQuotes(t *testing.T) {
+	data := Data{Schemas: []Schema{{Name: "public", Tables: []Table{{
+		Name:    "users",
+		Columns: []Column{{Name: `say "hi"`, Type: "text"}},
+	}}}}}
+	if _, err := ToDBML(data); err == nil || !strings.Contains(err.Error(), `say "hi"`) {
+		t.Errorf("expected an error naming the column, got %v", err)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		fpath string
+		raw   string
+		want  Format
+	}{
+		{name: "json extension", fpath: "schema.json", raw: "Table users {}", want: FormatJSON},
+		{name: "dbml extension", fpath: "schema.dbml", raw: "{}", want: FormatDBML},
+		{name: "json object", raw: ` {"schemas": []}`, want: FormatJSON},
+		{name: "json array", raw: `[{"name": "public"}]`, want: FormatJSON},
+		{name: "json with bom", raw: "\ufeff{\"schemas\": []}", want: FormatJSON},
+		{name: "empty", raw: "", want: FormatJSON},
+		{name: "table", raw: "Table users {\n  id int\n}", want: FormatDBML},
+		{name: "lowercase ref", raw: "ref: a.id > b.id", want: FormatDBML},
+		{name: "project after comments", raw: "// generated\n/* by hand\n */\nProject shop {}", want: FormatDBML},
+		{name: "enum", raw: "Enum status {\n  active\n}", want: FormatDBML},
+		{name: "plain text", raw: "hello world", want: FormatJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatOf(tt.fpath, []byte(tt.raw)); got != tt.want {
+				t.Errorf("formatOf() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package inspect
+
+import (
+	"reflect"
+)
+
+type Change string
+
+const (
+	Unchanged Change = ""
+	Added     Change = "added"
+	Removed   Change = "removed"
+	Modified  Change = "modified"
+)
+
+type TableDiff struct {
+	Change      Change
+	Columns     map[string]Change
+	Indexes     map[string]Change
+	ForeignKeys map[string]Change
+}
+
+type SchemaDiff struct {
+	Change Change
+	Tables map[string]*TableDiff
+}
+
+// Diff describes the changes needed to get from one Data to another, keyed by object names
+type Diff struct {
+	Schemas map[string]*SchemaDiff
+}
+
+func Compare(from, to Data) Diff {
+	diff := Diff{Schemas: make(map[string]*SchemaDiff)}
+	fromSchemas := schemasByName(from)
+	toSchemas := schemasByName(to)
+	for name, fromSchema := range fromSchemas {
+		toSchema, ok := toSchemas[name]
+		if !ok {
+			diff.Schemas[name] = &SchemaDiff{Change: Removed, Tables: allTables(fromSchema, Removed)}
+			continue
+		}
+		if schemaDiff := compareSchemas(fromSchema, toSchema); schemaDiff != nil {
+			diff.Schemas[name] = schemaDiff
+		}
+	}
+	for name, toSchema := range toSchemas {
+		if _, ok := fromSchemas[name]; !ok {
+			diff.Schemas[name] = &SchemaDiff{Change: Added, Tables: allTables(toSchema, Added)}
+		}
+	}
+	return diff
+}
+
+func (d Diff) Empty() bool {
+	return len(d.Schemas) == 0
+}
+
+// Table returns the diff of the given table, a zero TableDiff means no change
+func (d Diff) Table(schema, table string) TableDiff {
+	schemaDiff, ok := d.Schemas[schema]
+	if !ok {
+		return TableDiff{}
+	}
+	tableDiff, ok := schemaDiff.Tables[table]
+	if !ok {
+		return TableDiff{}
+	}
+	return *tableDiff
+}
+
+// TablesCount counts changed tables by the type of change
+func (d Diff) TablesCount() map[Change]int {
+	counts := make(map[Change]int)
+	for _, schemaDiff := range d.Schemas {
+		for _, tableDiff := range schemaDiff.Tables {
+			counts[tableDiff.Change]++
+		}
+	}
+	return counts
+}
+
+func compareSchemas(from, to Schema) *SchemaDiff {
+	schemaDiff := &SchemaDiff{Tables: make(map[string]*TableDiff)}
+	fromTables := tablesByName(from)
+	toTables := tablesByName(to)
+	for name, fromTable := range fromTables {
+		toTable, ok := toTables[name]
+		if !ok {
+			schemaDiff.Tables[name] = &TableDiff{Change: Removed}
+			continue
+		}
+		if tableDiff := compareTables(fromTable, toTable); tableDiff != nil {
+			schemaDiff.Tables[name] = tableDiff
+		}
+	}
+	for name := range toTables {
+		if _, ok := fromTables[name]; !ok {
+			schemaDiff.Tables[name] = &TableDiff{Change: Added}
+		}
+	}
+	if len(schemaDiff.Tables) == 0 && from.Attrs == to.Attrs {
+		return nil
+	}
+	schemaDiff.Change = Modified
+	return schemaDiff
+}
+
+func compareTables(from, to Table) *TableDiff {
+	tableDiff := &TableDiff{
+		Columns: compareNamed(from.Columns, to.Columns, func(col Column) string {
+			return col.Name
+		}, equalColumns),
+		Indexes: compareNamed(from.Indexes, to.Indexes, func(idx Index) string {
+			return idx.Name
+		}, deepEqual[Index]),
+		ForeignKeys: compareNamed(from.ForeignKeys, to.ForeignKeys, func(fk ForeignKey) string {
+			return fk.Name
+		}, deepEqual[ForeignKey]),
+	}
+	if len(tableDiff.Columns) == 0 && len(tableDiff.Indexes) == 0 && len(tableDiff.ForeignKeys) == 0 &&
+		from.Attrs == to.Attrs && reflect.DeepEqual(from.PrimaryKey, to.PrimaryKey) {
+		return nil
+	}
+	tableDiff.Change = Modified
+	return tableDiff
+}
+
+func deepEqual[T any](a, b T) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// equalColumns ignores defaults unless both sides report them, as inspections do not
+func equalColumns(from, to Column) bool {
+	if from.Default == "" || to.Default == "" {
+		from.Default, to.Default = "", ""
+	}
+	return reflect.DeepEqual(from, to)
+}
+
+func compareNamed[T any](from, to []T, name func(T) string, equal func(a, b T) bool) map[string]Change {
+	changes := make(map[string]Change)
+	toByName := make(map[string]T, len(to))
+	for _, item := range to {
+		toByName[name(item)] = item
+	}
+	fromNames := make(map[string]bool, len(from))
+	for _, fromItem := range from {
+		fromNames[name(fromItem)] = true
+		toItem, ok := toByName[name(fromItem)]
+		switch {
+		case !ok:
+			changes[name(fromItem)] = Removed
+		case !equal(fromItem, toItem):
+			changes[name(fromItem)] = Modified
+		}
+	}
+	for _, item := range to {
+		if !fromNames[name(item)] {
+			changes[name(item)] = Added
+		}
+	}
+	return changes
+}
+
+func allTables(schema Schema, change Change) map[string]*TableDiff {
+	tables := make(map[string]*TableDiff, len(schema.Tables))
+	for _, table := range schema.Tables {
+		tables[table.Name] = &TableDiff{Change: change}
+	}
+	return tables
+}
+
+func schemasByName(data Data) map[string]Schema {
+	schemas := make(map[string]Schema, len(data.Schemas))
+	for _, schema := range data.Schemas {
+		schemas[schema.Name] = schema
+	}
+	return schemas
+}
+
+func tablesByName(schema Schema) map[string]Table {
+	tables := make(map[string]Table, len(schema.Tables))
+	for _, table := range schema.Tables {
+		tables[table.Name] = table
+	}
+	return tables
+}
@@ -0,0 +1,57 @@
+package inspect
+
+import (
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// syncedMessage is printed by atlas instead of statements when there is nothing to migrate
+const syncedMessage = "Schemas are synced, no changes to be made."
+
+type DiffSQLParams struct {
+	From    string
+	To      string
+	DevURL  string
+	Schema  []string
+	Exclude []string
+	Vars    atlasexec.Vars
+}
+
+// DiffSQL runs 'atlas schema diff', returning the sql statements migrating From to To, empty when they are synced.
+// atlasexec does not expose schema diff, so it is run like inspect.
+func DiffSQL(ctx context.Context, cliPath string, params *DiffSQLParams, stderr io.Writer) (string, error) {
+	if cliPath == "" {
+		return "", fmt.Errorf("atlas cli path is not set")
+	}
+	args := []string{"schema", "diff", "--from", params.From, "--to", params.To}
+	if params.DevURL != "" {
+		args = append(args, "--dev-url", params.DevURL)
+	}
+	if len(params.Schema) > 0 {
+		args = append(args, "--schema", strings.Join(params.Schema, ","))
+	}
+	if len(params.Exclude) > 0 {
+		args = append(args, "--exclude", strings.Join(params.Exclude, ","))
+	}
+	args = append(args, params.Vars.AsArgs()...)
+	raw, err := runAtlas(ctx, cliPath, args, stderr)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(string(raw)) == syncedMessage {
+		return "", nil
+	}
+	return string(raw), nil
+}
+
+// HashMigrationDir runs 'atlas migrate hash', required after adding files to the migration dir
+func HashMigrationDir(ctx context.Context, cliPath, dirURL string) error {
+	if cliPath == "" {
+		return fmt.Errorf("atlas cli path is not set")
+	}
+	_, err := runAtlas(ctx, cliPath, []string{"migrate", "hash", "--dir", dirURL}, nil)
+	return err
+}
@@ -5,35 +5,46 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/samber/lo"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 const cliErrorPrefix = "Error: "
 const jsonFormat = "{{ json . }}"
 
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatDBML Format = "dbml"
+)
+
+var Formats = []Format{FormatJSON, FormatDBML}
+
 type Params struct {
 	atlasexec.SchemaInspectParams
 	FromFilePath string
 	AtlasCliPath string
 }
 
-func Inspect(ctx context.Context, params *Params) (*Data, error) {
-	atlasClient, err := atlasexec.NewClient("", params.AtlasCliPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create atlas client: %w", err)
+// schemaFileExts are schema definitions (rather than inspection outputs), which only atlas itself can read
+var schemaFileExts = []string{".hcl", ".sql"}
+
+// Inspect runs 'atlas schema inspect', the cli stderr is streamed to the given writer when not nil
+func Inspect(ctx context.Context, params *Params, stderr io.Writer) (*Data, error) {
+	if params.AtlasCliPath == "" {
+		return nil, fmt.Errorf("atlas cli path is not set")
 	}
-	params.SchemaInspectParams.Format = jsonFormat
-	raw, err := atlasClient.SchemaInspect(ctx, &params.SchemaInspectParams)
+	inspectParams := params.SchemaInspectParams
+	inspectParams.Format = jsonFormat
+	raw, err := runAtlas(ctx, params.AtlasCliPath, schemaInspectArgs(&inspectParams), stderr)
 	if err != nil {
-		if strings.HasPrefix(err.Error(), cliErrorPrefix) {
-			// avoid printing cobra error prefix twice...
-			return nil, fmt.Errorf("%s", strings.TrimPrefix(err.Error(), cliErrorPrefix))
-		}
 		return nil, err
 	}
-	return unmarshal([]byte(raw))
+	return unmarshal(raw)
 }
 
 func LoadFromFile(fpath string) (*Data, error) {
@@ -46,7 +57,73 @@ func LoadFromFile(fpath string) (*Data, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file at '%s': %w", fpath, err)
 	}
-	return unmarshal(raw)
+	return Decode(raw, formatOf(fpath, raw))
+}
+
+// IsSchemaFile reports whether the file is an atlas schema definition, to be inspected with InspectFile
+func IsSchemaFile(fpath string) bool {
+	return lo.Contains(schemaFileExts, strings.ToLower(filepath.Ext(fpath)))
+}
+
+// InspectFile inspects an atlas HCL or SQL schema file, which requires the atlas cli and usually a dev database
+func InspectFile(ctx context.Context, params *Params, fpath string, stderr io.Writer) (*Data, error) {
+	fpath, err := filepath.Abs(fpath)
+	if err != nil {
+		return nil, err
+	}
+	fileParams := *params
+	fileParams.URL = "file://" + filepath.ToSlash(fpath)
+	return Inspect(ctx, &fileParams, stderr)
+}
+
+// LoadFromBytes decodes a schema read beforehand, guessing its format by its contents
+func LoadFromBytes(raw []byte) (*Data, error) {
+	return Decode(raw, formatOf("", raw))
+}
+
+// formatOf guesses the format of a schema file by its extension, falling back to its contents.
+// Content not starting with a dbml element is taken as json, to report why it is not valid json.
+func formatOf(fpath string, raw []byte) Format {
+	switch strings.ToLower(filepath.Ext(fpath)) {
+	case ".json":
+		return FormatJSON
+	case ".dbml":
+		return FormatDBML
+	}
+	if isDBML(raw) {
+		return FormatDBML
+	}
+	return FormatJSON
+}
+
+func Decode(raw []byte, format Format) (*Data, error) {
+	switch format {
+	case FormatJSON:
+		return unmarshal(raw)
+	case FormatDBML:
+		return parseDBML(raw)
+	default:
+		return nil, fmt.Errorf("unsupported format '%s'", format)
+	}
+}
+
+func Encode(data Data, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		raw, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(raw, '\n'), nil
+	case FormatDBML:
+		out, err := ToDBML(data)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(out), nil
+	default:
+		return nil, fmt.Errorf("unsupported format '%s'", format)
+	}
 }
 
 func unmarshal(raw []byte) (*Data, error) {
@@ -12,6 +12,8 @@ type Column struct {
 	Name string `json:"name"`
 	Type string `json:"type,omitempty"`
 	Null bool   `json:"null,omitempty"`
+	// Default is set by schema files only, atlas inspection does not report it so the tui reads it off live databases
+	Default string `json:"default,omitempty"`
 	Attrs
 }
 
@@ -0,0 +1,35 @@
+package inspect
+
+import "github.com/samber/lo"
+
+// Components groups the tables of the schema connected by foreign keys in either direction.
+// Groups are ordered by their first table, and tables keep their order within the schema.
+func (s Schema) Components() [][]string {
+	names := lo.Map(s.Tables, func(t Table, _ int) string { return t.Name })
+	parent := lo.SliceToMap(names, func(name string) (string, string) { return name, name })
+	var root func(name string) string
+	root = func(name string) string {
+		if parent[name] != name {
+			parent[name] = root(parent[name])
+		}
+		return parent[name]
+	}
+	for _, t := range s.Tables {
+		for _, fk := range t.ForeignKeys {
+			// references to other schemas are not part of this schema graph
+			if _, ok := parent[fk.References.Table]; ok {
+				parent[root(fk.References.Table)] = root(t.Name)
+			}
+		}
+	}
+	var roots []string
+	groups := make(map[string][]string)
+	for _, name := range names {
+		r := root(name)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], name)
+	}
+	return lo.Map(roots, func(r string, _ int) []string { return groups[r] })
+}
@@ -0,0 +1,435 @@
+package migrate
+
+import (
+	"strings"
+	"unicode"
+)
+
+type ChangeKind string
+
+const (
+	CreateSchema   ChangeKind = "create schema"
+	DropSchema     ChangeKind = "drop schema"
+	CreateTable    ChangeKind = "create table"
+	DropTable      ChangeKind = "drop table"
+	RenameTable    ChangeKind = "rename table"
+	AddColumn      ChangeKind = "add column"
+	DropColumn     ChangeKind = "drop column"
+	ModifyColumn   ChangeKind = "modify column"
+	RenameColumn   ChangeKind = "rename column"
+	AddIndex       ChangeKind = "add index"
+	DropIndex      ChangeKind = "drop index"
+	RenameIndex    ChangeKind = "rename index"
+	AddPrimaryKey  ChangeKind = "add primary key"
+	DropPrimaryKey ChangeKind = "drop primary key"
+	AddForeignKey  ChangeKind = "add foreign key"
+	DropForeignKey ChangeKind = "drop foreign key"
+	AddConstraint  ChangeKind = "add constraint"
+	DropConstraint ChangeKind = "drop constraint"
+	ModifyData     ChangeKind = "modify data"
+	OtherChange    ChangeKind = "other"
+)
+
+// destructiveKinds lose data once applied
+var destructiveKinds = map[ChangeKind]string{
+	DropSchema: "drops the schema along with all of its data",
+	DropTable:  "drops the table along with all of its data",
+	DropColumn: "drops the column along with all of its data",
+}
+
+// Change describes a single change made by a planned sql statement.
+// Table is empty when the statement does not name it, e.g. dropping an index in postgres.
+type Change struct {
+	Kind    ChangeKind
+	Schema  string
+	Table   string
+	Object  string
+	Warning string
+	SQL     string
+}
+
+func (c Change) Destructive() bool {
+	_, ok := destructiveKinds[c.Kind]
+	return ok
+}
+
+// Describe breaks a planned sql statement into the changes it makes, on a best effort basis
+func Describe(sql string) []Change {
+	toks := tokenize(sql)
+	p := &stmtParser{toks: toks}
+	var changes []Change
+	switch {
+	case p.accept("CREATE", "SCHEMA"), p.accept("CREATE", "DATABASE"):
+		p.accept("IF", "NOT", "EXISTS")
+		_, name := p.name()
+		changes = append(changes, Change{Kind: CreateSchema, Schema: name, Object: name})
+	case p.accept("DROP", "SCHEMA"), p.accept("DROP", "DATABASE"):
+		p.accept("IF", "EXISTS")
+		_, name := p.name()
+		changes = append(changes, Change{Kind: DropSchema, Schema: name, Object: name})
+	case p.accept("CREATE", "TABLE"):
+		p.accept("IF", "NOT", "EXISTS")
+		schema, name := p.name()
+		changes = append(changes, Change{Kind: CreateTable, Schema: schema, Table: name, Object: name})
+	case p.accept("DROP", "TABLE"):
+		p.accept("IF", "EXISTS")
+		for {
+			schema, name := p.name()
+			changes = append(changes, Change{Kind: DropTable, Schema: schema, Table: name, Object: name})
+			if !p.accept(",") {
+				break
+			}
+		}
+	case p.accept("RENAME", "TABLE"):
+		schema, name := p.name()
+		p.accept("TO")
+		_, to := p.name()
+		changes = append(changes, Change{Kind: RenameTable, Schema: schema, Table: name, Object: name + " to " + to})
+	case p.accept("CREATE", "UNIQUE", "INDEX"):
+		changes = append(changes, p.createIndex(true))
+	case p.accept("CREATE", "INDEX"):
+		changes = append(changes, p.createIndex(false))
+	case p.accept("DROP", "INDEX"):
+		p.accept("CONCURRENTLY")
+		p.accept("IF", "EXISTS")
+		schema, name := p.name()
+		change := Change{Kind: DropIndex, Schema: schema, Object: name}
+		if p.accept("ON") {
+			change.Schema, change.Table = p.name()
+		}
+		changes = append(changes, change)
+	case p.accept("ALTER", "INDEX"):
+		p.accept("IF", "EXISTS")
+		schema, name := p.name()
+		change := Change{Kind: OtherChange, Schema: schema, Object: name}
+		if p.accept("RENAME", "TO") {
+			_, to := p.name()
+			change.Kind, change.Object = RenameIndex, name+" to "+to
+		}
+		changes = append(changes, change)
+	case p.accept("INSERT", "INTO"), p.accept("UPDATE"), p.accept("DELETE", "FROM"), p.accept("TRUNCATE"):
+		p.accept("TABLE")
+		p.accept("ONLY")
+		schema, name := p.name()
+		changes = append(changes, Change{Kind: ModifyData, Schema: schema, Table: name, Object: name})
+	case p.accept("ALTER", "TABLE"):
+		p.accept("IF", "EXISTS")
+		p.accept("ONLY")
+		schema, table := p.name()
+		for _, clause := range splitClauses(p.rest()) {
+			change := describeClause(clause)
+			change.Schema, change.Table = schema, table
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) == 0 {
+		changes = append(changes, Change{Kind: OtherChange})
+	}
+	for i := range changes {
+		changes[i].SQL = sql
+		if warning, ok := destructiveKinds[changes[i].Kind]; ok {
+			changes[i].Warning = warning
+		}
+	}
+	return changes
+}
+
+func (p *stmtParser) createIndex(unique bool) Change {
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "NOT", "EXISTS")
+	_, name := p.name()
+	change := Change{Kind: AddIndex, Object: name}
+	if p.accept("ON") {
+		change.Schema, change.Table = p.name()
+	}
+	if unique {
+		change.Warning = "fails if existing rows are not unique"
+	}
+	return change
+}
+
+// describeClause describes a single clause of an ALTER TABLE statement
+func describeClause(toks []token) Change {
+	p := &stmtParser{toks: toks}
+	switch {
+	case p.accept("ADD"):
+		return describeAdd(p)
+	case p.accept("DROP"):
+		return describeDrop(p)
+	case p.accept("ALTER"), p.accept("MODIFY"):
+		p.accept("COLUMN")
+		_, name := p.name()
+		change := Change{Kind: ModifyColumn, Object: name}
+		rest := " " + p.upperRest() + " "
+		switch {
+		case strings.Contains(rest, " SET NOT NULL "):
+			change.Warning = "fails if existing rows hold nulls"
+		case strings.Contains(rest, " TYPE ") || toks[0].upper() == "MODIFY":
+			change.Warning = "changing the column type may fail or truncate data"
+		}
+		return change
+	case p.accept("CHANGE"):
+		p.accept("COLUMN")
+		_, name := p.name()
+		_, to := p.name()
+		change := Change{Kind: ModifyColumn, Object: name, Warning: "changing the column type may fail or truncate data"}
+		if to != name {
+			change.Object = name + " to " + to
+		}
+		return change
+	case p.accept("RENAME", "COLUMN"):
+		_, name := p.name()
+		p.accept("TO")
+		_, to := p.name()
+		return Change{Kind: RenameColumn, Object: name + " to " + to}
+	case p.accept("RENAME", "INDEX"), p.accept("RENAME", "KEY"):
+		_, name := p.name()
+		p.accept("TO")
+		_, to := p.name()
+		return Change{Kind: RenameIndex, Object: name + " to " + to}
+	case p.accept("RENAME"):
+		if !p.accept("TO") {
+			p.accept("AS")
+		}
+		_, to := p.name()
+		return Change{Kind: RenameTable, Object: "to " + to}
+	}
+	return Change{Kind: OtherChange, Object: joinTokens(toks)}
+}
+
+func describeAdd(p *stmtParser) Change {
+	switch {
+	case p.accept("CONSTRAINT"):
+		_, name := p.name()
+		return describeConstraint(p, name)
+	case p.peek("PRIMARY"), p.peek("FOREIGN"), p.peek("UNIQUE"), p.peek("CHECK"):
+		return describeConstraint(p, "")
+	case p.accept("INDEX"), p.accept("KEY"), p.accept("FULLTEXT"), p.accept("SPATIAL"):
+		p.accept("INDEX")
+		p.accept("KEY")
+		_, name := p.name()
+		return Change{Kind: AddIndex, Object: name}
+	}
+	p.accept("COLUMN")
+	p.accept("IF", "NOT", "EXISTS")
+	_, name := p.name()
+	change := Change{Kind: AddColumn, Object: name}
+	rest := " " + p.upperRest() + " "
+	if strings.Contains(rest, " NOT NULL ") && !strings.Contains(rest, " DEFAULT ") &&
+		!strings.Contains(rest, " AUTO_INCREMENT ") && !strings.Contains(rest, " IDENTITY ") && !strings.Contains(rest, " SERIAL ") {
+		change.Warning = "adding a non-nullable column without a default fails on tables with rows"
+	}
+	return change
+}
+
+func describeDrop(p *stmtParser) Change {
+	switch {
+	case p.accept("CONSTRAINT"), p.accept("CHECK"):
+		p.accept("IF", "EXISTS")
+		_, name := p.name()
+		return Change{Kind: DropConstraint, Object: name}
+	case p.accept("FOREIGN", "KEY"):
+		_, name := p.name()
+		return Change{Kind: DropForeignKey, Object: name}
+	case p.accept("PRIMARY", "KEY"):
+		return Change{Kind: DropPrimaryKey}
+	case p.accept("INDEX"), p.accept("KEY"):
+		_, name := p.name()
+		return Change{Kind: DropIndex, Object: name}
+	}
+	p.accept("COLUMN")
+	p.accept("IF", "EXISTS")
+	_, name := p.name()
+	return Change{Kind: DropColumn, Object: name}
+}
+
+// describeConstraint describes an added constraint, mysql allows naming some of them after their keywords
+func describeConstraint(p *stmtParser, name string) Change {
+	named := func() string {
+		if name == "" {
+			_, name = p.name()
+		}
+		return name
+	}
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		return Change{Kind: AddPrimaryKey, Object: name, Warning: "fails if existing rows hold nulls or duplicates"}
+	case p.accept("FOREIGN", "KEY"):
+		return Change{Kind: AddForeignKey, Object: named(), Warning: "fails if existing rows reference missing rows"}
+	case p.accept("UNIQUE"):
+		if !p.accept("INDEX") {
+			p.accept("KEY")
+		}
+		return Change{Kind: AddIndex, Object: named(), Warning: "fails if existing rows are not unique"}
+	case p.accept("CHECK"):
+		return Change{Kind: AddConstraint, Object: name, Warning: "fails if existing rows violate the check"}
+	}
+	return Change{Kind: AddConstraint, Object: name}
+}
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+func (t token) upper() string {
+	if t.quoted {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+type stmtParser struct {
+	toks []token
+	pos  int
+}
+
+// accept consumes the given keywords if they are next, in order
+func (p *stmtParser) accept(words ...string) bool {
+	if p.pos+len(words) > len(p.toks) {
+		return false
+	}
+	for i, word := range words {
+		if p.toks[p.pos+i].upper() != word {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *stmtParser) peek(word string) bool {
+	return p.pos < len(p.toks) && p.toks[p.pos].upper() == word
+}
+
+// name consumes a possibly qualified name, returning its qualifier (schema) and last part
+func (p *stmtParser) name() (qualifier string, name string) {
+	var parts []string
+	for p.pos < len(p.toks) {
+		tok := p.toks[p.pos]
+		if !tok.quoted && !isIdentToken(tok.text) {
+			break
+		}
+		parts = append(parts, tok.text)
+		p.pos++
+		if p.pos >= len(p.toks) || p.toks[p.pos].text != "." || p.toks[p.pos].quoted {
+			break
+		}
+		p.pos++
+	}
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		return "", parts[0]
+	default:
+		return parts[len(parts)-2], parts[len(parts)-1]
+	}
+}
+
+func (p *stmtParser) rest() []token {
+	return p.toks[p.pos:]
+}
+
+func (p *stmtParser) upperRest() string {
+	words := make([]string, 0, len(p.toks)-p.pos)
+	for _, tok := range p.rest() {
+		words = append(words, tok.upper())
+	}
+	return strings.Join(words, " ")
+}
+
+// splitClauses splits tokens by top level commas, commas within parentheses belong to their clause
+func splitClauses(toks []token) [][]token {
+	var clauses [][]token
+	depth, start := 0, 0
+	for i, tok := range toks {
+		if tok.quoted {
+			continue
+		}
+		switch tok.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",", ";":
+			if depth == 0 {
+				if i > start {
+					clauses = append(clauses, toks[start:i])
+				}
+				start = i + 1
+			}
+		}
+	}
+	if start < len(toks) {
+		clauses = append(clauses, toks[start:])
+	}
+	return clauses
+}
+
+func isIdentToken(text string) bool {
+	return text != "" && (unicode.IsLetter(rune(text[0])) || text[0] == '_')
+}
+
+// tokenize splits sql into words, quoted identifiers and punctuation. String literals are kept as single tokens.
+func tokenize(sql string) []token {
+	var toks []token
+	runes := []rune(sql)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := strings.Index(string(runes[i+2:]), "*/")
+			if end < 0 {
+				return toks
+			}
+			i += 2 + len([]rune(string(runes[i+2:])[:end])) + 1
+		case r == '"' || r == '`' || r == '[':
+			closing := map[rune]rune{'"': '"', '`': '`', '[': ']'}[r]
+			j := i + 1
+			for j < len(runes) && runes[j] != closing {
+				j++
+			}
+			toks = append(toks, token{text: string(runes[i+1 : min(j, len(runes))]), quoted: true})
+			i = j
+		case r == '\'':
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] != '\'' {
+					continue
+				}
+				// a doubled quote escapes itself
+				if j+1 < len(runes) && runes[j+1] == '\'' {
+					j++
+					continue
+				}
+				break
+			}
+			toks = append(toks, token{text: string(runes[i:min(j+1, len(runes))])})
+			i = j
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '$') {
+				j++
+			}
+			toks = append(toks, token{text: string(runes[i:j])})
+			i = j - 1
+		default:
+			toks = append(toks, token{text: string(r)})
+		}
+	}
+	return toks
+}
+
+func joinTokens(toks []token) string {
+	texts := make([]string, 0, len(toks))
+	for _, tok := range toks {
+		texts = append(texts, tok.text)
+	}
+	return strings.Join(texts, " ")
+}
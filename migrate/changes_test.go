@@ -0,0 +1,266 @@
+package migrate
+
+import (
+	"reflect"
+	"testing"
+)
+
+// describedChange is the part of a change the tests check, warnings are checked for being set only
+type describedChange struct {
+	Kind        ChangeKind
+	Schema      string
+	Table       string
+	Object      string
+	Warned      bool
+	Destructive bool
+}
+
+func describe(sql string) []describedChange {
+	var described []describedChange
+	for _, change := range Describe(sql) {
+		described = append(described, describedChange{
+			Kind:        change.Kind,
+			Schema:      change.Schema,
+			Table:       change.Table,
+			Object:      change.Object,
+			Warned:      change.Warning != "",
+			Destructive: change.Destructive(),
+		})
+	}
+	return described
+}
+
+type describeTest struct {
+	name string
+	sql  string
+	want []describedChange
+}
+
+func runDescribeTests(t *testing.T, tests []describeTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describe(tt.sql); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Describe(%q)\ngot:  %+v\nwant: %+v", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribePostgres(t *testing.T) {
+	runDescribeTests(t, []describeTest{
+		{
+			name: "drop column",
+			sql:  `ALTER TABLE "public"."users" DROP COLUMN "email";`,
+			want: []describedChange{{Kind: DropColumn, Schema: "public", Table: "users", Object: "email", Warned: true, Destructive: true}},
+		},
+		{
+			name: "drop column if exists",
+			sql:  `ALTER TABLE IF EXISTS ONLY "public"."users" DROP COLUMN IF EXISTS "email";`,
+			want: []describedChange{{Kind: DropColumn, Schema: "public", Table: "users", Object: "email", Warned: true, Destructive: true}},
+		},
+		{
+			name: "rename column",
+			sql:  `ALTER TABLE "public"."users" RENAME COLUMN "name" TO "full_name";`,
+			want: []describedChange{{Kind: RenameColumn, Schema: "public", Table: "users", Object: "name to full_name"}},
+		},
+		{
+			name: "rename table",
+			sql:  `ALTER TABLE "public"."users" RENAME TO "members";`,
+			want: []describedChange{{Kind: RenameTable, Schema: "public", Table: "users", Object: "to members"}},
+		},
+		{
+			name: "alter column type and nullability",
+			sql:  `ALTER TABLE "public"."users" ALTER COLUMN "age" TYPE bigint, ALTER COLUMN "email" SET NOT NULL, ALTER COLUMN "bio" SET DEFAULT '';`,
+			want: []describedChange{
+				{Kind: ModifyColumn, Schema: "public", Table: "users", Object: "age", Warned: true},
+				{Kind: ModifyColumn, Schema: "public", Table: "users", Object: "email", Warned: true},
+				{Kind: ModifyColumn, Schema: "public", Table: "users", Object: "bio"},
+			},
+		},
+		{
+			name: "multiple changes",
+			sql:  `ALTER TABLE "public"."users" ADD COLUMN "org_id" integer NOT NULL, ADD COLUMN "note" text, DROP CONSTRAINT "users_check", ADD CONSTRAINT "users_org_fk" FOREIGN KEY ("org_id") REFERENCES "public"."orgs" ("id");`,
+			want: []describedChange{
+				{Kind: AddColumn, Schema: "public", Table: "users", Object: "org_id", Warned: true},
+				{Kind: AddColumn, Schema: "public", Table: "users", Object: "note"},
+				{Kind: DropConstraint, Schema: "public", Table: "users", Object: "users_check"},
+				{Kind: AddForeignKey, Schema: "public", Table: "users", Object: "users_org_fk", Warned: true},
+			},
+		},
+		{
+			name: "add column with default or identity",
+			sql:  `ALTER TABLE "public"."users" ADD COLUMN "active" boolean NOT NULL DEFAULT true, ADD COLUMN "seq" bigint NOT NULL GENERATED ALWAYS AS IDENTITY;`,
+			want: []describedChange{
+				{Kind: AddColumn, Schema: "public", Table: "users", Object: "active"},
+				{Kind: AddColumn, Schema: "public", Table: "users", Object: "seq"},
+			},
+		},
+		{
+			name: "add primary key",
+			sql:  `ALTER TABLE ONLY "public"."users" ADD CONSTRAINT "users_pkey" PRIMARY KEY ("id");`,
+			want: []describedChange{{Kind: AddPrimaryKey, Schema: "public", Table: "users", Object: "users_pkey", Warned: true}},
+		},
+		{
+			name: "drop index without table",
+			sql:  `DROP INDEX "public"."users_email_idx";`,
+			want: []describedChange{{Kind: DropIndex, Schema: "public", Object: "users_email_idx"}},
+		},
+		{
+			name: "drop index concurrently",
+			sql:  `DROP INDEX CONCURRENTLY IF EXISTS "users_email_idx";`,
+			want: []describedChange{{Kind: DropIndex, Object: "users_email_idx"}},
+		},
+		{
+			name: "create unique index",
+			sql:  `CREATE UNIQUE INDEX CONCURRENTLY "users_email" ON "public"."users" ("email");`,
+			want: []describedChange{{Kind: AddIndex, Schema: "public", Table: "users", Object: "users_email", Warned: true}},
+		},
+		{
+			name: "rename index",
+			sql:  `ALTER INDEX "public"."users_email" RENAME TO "users_email_key";`,
+			want: []describedChange{{Kind: RenameIndex, Schema: "public", Object: "users_email to users_email_key"}},
+		},
+		{
+			name: "quoted identifiers with dots, commas and keywords",
+			sql:  `ALTER TABLE "my.schema"."order, items" DROP COLUMN "drop", ADD COLUMN "a,b" int;`,
+			want: []describedChange{
+				{Kind: DropColumn, Schema: "my.schema", Table: "order, items", Object: "drop", Warned: true, Destructive: true},
+				{Kind: AddColumn, Schema: "my.schema", Table: "order, items", Object: "a,b"},
+			},
+		},
+		{
+			name: "drop tables",
+			sql:  `DROP TABLE IF EXISTS "public"."a", "public"."b";`,
+			want: []describedChange{
+				{Kind: DropTable, Schema: "public", Table: "a", Object: "a", Warned: true, Destructive: true},
+				{Kind: DropTable, Schema: "public", Table: "b", Object: "b", Warned: true, Destructive: true},
+			},
+		},
+		{
+			name: "drop schema",
+			sql:  `DROP SCHEMA IF EXISTS "audit" CASCADE;`,
+			want: []describedChange{{Kind: DropSchema, Schema: "audit", Object: "audit", Warned: true, Destructive: true}},
+		},
+		{
+			name: "not tied to a table",
+			sql:  `CREATE EXTENSION IF NOT EXISTS "pgcrypto";`,
+			want: []describedChange{{Kind: OtherChange}},
+		},
+	})
+}
+
+func TestDescribeMySQL(t *testing.T) {
+	runDescribeTests(t, []describeTest{
+		{
+			name: "drop, modify and change columns",
+			sql:  "ALTER TABLE `shop`.`orders` DROP COLUMN `note`, MODIFY COLUMN `total` decimal(12,2) NOT NULL, CHANGE `qty` `quantity` int NOT NULL;",
+			want: []describedChange{
+				{Kind: DropColumn, Schema: "shop", Table: "orders", Object: "note", Warned: true, Destructive: true},
+				{Kind: ModifyColumn, Schema: "shop", Table: "orders", Object: "total", Warned: true},
+				{Kind: ModifyColumn, Schema: "shop", Table: "orders", Object: "qty to quantity", Warned: true},
+			},
+		},
+		{
+			name: "drop column without keyword",
+			sql:  "ALTER TABLE `orders` DROP `note`;",
+			want: []describedChange{{Kind: DropColumn, Table: "orders", Object: "note", Warned: true, Destructive: true}},
+		},
+		{
+			name: "rename column",
+			sql:  "ALTER TABLE `orders` RENAME COLUMN `qty` TO `quantity`;",
+			want: []describedChange{{Kind: RenameColumn, Table: "orders", Object: "qty to quantity"}},
+		},
+		{
+			name: "drop index with table",
+			sql:  "DROP INDEX `orders_code` ON `shop`.`orders`;",
+			want: []describedChange{{Kind: DropIndex, Schema: "shop", Table: "orders", Object: "orders_code"}},
+		},
+		{
+			name: "drop foreign key and its index",
+			sql:  "ALTER TABLE `orders` DROP FOREIGN KEY `orders_user_fk`, DROP INDEX `orders_user_fk`;",
+			want: []describedChange{
+				{Kind: DropForeignKey, Table: "orders", Object: "orders_user_fk"},
+				{Kind: DropIndex, Table: "orders", Object: "orders_user_fk"},
+			},
+		},
+		{
+			name: "add indexes",
+			sql:  "ALTER TABLE `orders` ADD UNIQUE INDEX `orders_code` (`code`), ADD INDEX `orders_user_date` (`user_id`, `created_at`);",
+			want: []describedChange{
+				{Kind: AddIndex, Table: "orders", Object: "orders_code", Warned: true},
+				{Kind: AddIndex, Table: "orders", Object: "orders_user_date"},
+			},
+		},
+		{
+			name: "rename index",
+			sql:  "ALTER TABLE `orders` RENAME INDEX `a` TO `b`;",
+			want: []describedChange{{Kind: RenameIndex, Table: "orders", Object: "a to b"}},
+		},
+		{
+			name: "drop primary key",
+			sql:  "ALTER TABLE `orders` DROP PRIMARY KEY;",
+			want: []describedChange{{Kind: DropPrimaryKey, Table: "orders"}},
+		},
+		{
+			name: "add auto increment column",
+			sql:  "ALTER TABLE `orders` ADD COLUMN `id` bigint NOT NULL AUTO_INCREMENT;",
+			want: []describedChange{{Kind: AddColumn, Table: "orders", Object: "id"}},
+		},
+		{
+			name: "rename table",
+			sql:  "RENAME TABLE `shop`.`carts` TO `shop`.`baskets`;",
+			want: []describedChange{{Kind: RenameTable, Schema: "shop", Table: "carts", Object: "carts to baskets"}},
+		},
+		{
+			name: "create database",
+			sql:  "CREATE DATABASE `shop`;",
+			want: []describedChange{{Kind: CreateSchema, Schema: "shop", Object: "shop"}},
+		},
+	})
+}
+
+func TestDescribeSQLite(t *testing.T) {
+	runDescribeTests(t, []describeTest{
+		{
+			name: "drop column",
+			sql:  `ALTER TABLE "users" DROP COLUMN "email";`,
+			want: []describedChange{{Kind: DropColumn, Table: "users", Object: "email", Warned: true, Destructive: true}},
+		},
+		{
+			name: "rename column",
+			sql:  `ALTER TABLE users RENAME COLUMN name TO full_name;`,
+			want: []describedChange{{Kind: RenameColumn, Table: "users", Object: "name to full_name"}},
+		},
+		{
+			name: "rename table",
+			sql:  `ALTER TABLE users RENAME TO members;`,
+			want: []describedChange{{Kind: RenameTable, Table: "users", Object: "to members"}},
+		},
+		{
+			name: "add column with a literal holding commas",
+			sql:  `ALTER TABLE "users" ADD COLUMN "tags" text NOT NULL DEFAULT 'a, b';`,
+			want: []describedChange{{Kind: AddColumn, Table: "users", Object: "tags"}},
+		},
+		{
+			name: "drop index",
+			sql:  `DROP INDEX IF EXISTS "users_email";`,
+			want: []describedChange{{Kind: DropIndex, Object: "users_email"}},
+		},
+		{
+			name: "copy data",
+			sql:  `INSERT INTO "new_users" ("id") SELECT "id" FROM "users";`,
+			want: []describedChange{{Kind: ModifyData, Table: "new_users", Object: "new_users"}},
+		},
+		{
+			name: "commented",
+			sql:  "-- drop the old table\n/* it is replaced */ DROP TABLE `users`;",
+			want: []describedChange{{Kind: DropTable, Table: "users", Object: "users", Warned: true, Destructive: true}},
+		},
+		{
+			name: "pragma",
+			sql:  `PRAGMA foreign_keys = off;`,
+			want: []describedChange{{Kind: OtherChange}},
+		},
+	})
+}
@@ -0,0 +1,135 @@
+package migrate
+
+import (
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LintCategory groups lint diagnostics by the kind of risk they report
+type LintCategory string
+
+const (
+	Destructive          LintCategory = "destructive"
+	DataDependent        LintCategory = "data dependent"
+	BackwardIncompatible LintCategory = "backward incompatible"
+	OtherLintCategory    LintCategory = "other"
+)
+
+// lintCodePrefixes are the analyzer code prefixes of each category, as documented in https://atlasgo.io/lint/analyzers
+var lintCodePrefixes = map[string]LintCategory{
+	"DS": Destructive,
+	"MF": DataDependent,
+	"BC": BackwardIncompatible,
+}
+
+// Lint is the result of 'atlas migrate lint' over a migration dir
+type Lint struct {
+	DirURL      string
+	Diagnostics []Diagnostic
+	Errors      []string
+}
+
+// Diagnostic is a single lint finding, bound to the statement and the table it was found at
+type Diagnostic struct {
+	File      string
+	Code      string
+	Category  LintCategory
+	Report    string
+	Text      string
+	Statement string
+	Schema    string
+	Table     string
+	Fixes     []string
+}
+
+// LintCategoryOf maps atlas analyzer codes to their category, e.g. DS103 is destructive
+func LintCategoryOf(code string) LintCategory {
+	for prefix, category := range lintCodePrefixes {
+		if strings.HasPrefix(code, prefix) {
+			return category
+		}
+	}
+	return OtherLintCategory
+}
+
+// NewLint flattens the lint report into diagnostics, resolving the statement and table of each
+func NewLint(dirURL string, report *atlasexec.SummaryReport) *Lint {
+	l := &Lint{DirURL: dirURL}
+	for _, step := range report.Steps {
+		if step.Error != "" {
+			l.Errors = append(l.Errors, fmt.Sprintf("%s: %s", step.Name, step.Error))
+		}
+	}
+	for _, file := range report.Files {
+		if file.Error != "" {
+			l.Errors = append(l.Errors, fmt.Sprintf("%s: %s", file.Name, file.Error))
+		}
+		for _, r := range file.Reports {
+			for _, diag := range r.Diagnostics {
+				d := Diagnostic{
+					File:      file.Name,
+					Code:      diag.Code,
+					Category:  LintCategoryOf(diag.Code),
+					Report:    r.Text,
+					Text:      diag.Text,
+					Statement: statementAt(file.Text, diag.Pos),
+				}
+				for _, change := range Describe(d.Statement) {
+					if change.Table != "" {
+						d.Schema, d.Table = change.Schema, change.Table
+						break
+					}
+				}
+				for _, fix := range append(diag.SuggestedFixes, r.SuggestedFixes...) {
+					d.Fixes = append(d.Fixes, fix.Message)
+				}
+				l.Diagnostics = append(l.Diagnostics, d)
+			}
+		}
+	}
+	return l
+}
+
+// statementAt returns the statement found at the given byte position of the file
+func statementAt(text string, pos int) string {
+	if pos < 0 || pos >= len(text) {
+		return ""
+	}
+	start := strings.LastIndex(text[:pos], ";") + 1
+	end := strings.Index(text[pos:], ";")
+	if end < 0 {
+		end = len(text)
+	} else {
+		end += pos + 1
+	}
+	// drop the comments leading the statement
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(text[start:end]), "\n") {
+		if len(lines) == 0 && strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CountFiles counts the migration files of a local dir
+func CountFiles(dirURL string) (int, error) {
+	dir, err := DirPath(dirURL)
+	if err != nil {
+		return 0, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return 0, err
+	}
+	if len(files) == 0 {
+		if _, err = os.Stat(dir); err != nil {
+			return 0, fmt.Errorf("failed to read migration dir: %w", err)
+		}
+	}
+	return len(files), nil
+}
@@ -0,0 +1,217 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"github.com/samber/lo"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDirURL is where atlas keeps migration files when the env does not set a migration dir
+	DefaultDirURL = "file://migrations"
+	versionLayout = "20060102150405"
+	// maxVersionBumps bounds how far past the current second a new migration version is searched
+	maxVersionBumps = 60
+)
+
+var (
+	// atlas describes each planned statement with a comment, e.g. `-- Modify "users" table` or `-- Create index "idx" to table: "users"`
+	commentTablePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`table: "([^"]+)"`),
+		regexp.MustCompile(`"([^"]+)" table`),
+	}
+)
+
+// Statement is a single planned sql statement, along with the comment atlas gave it.
+// Schema is empty when the statement does not qualify its table
+type Statement struct {
+	Comment string
+	SQL     string
+	Schema  string
+	Table   string
+}
+
+// Group holds the statements of a single table, an empty table holds statements not bound to any table
+type Group struct {
+	Schema     string
+	Table      string
+	Statements []Statement
+}
+
+// Holds tells whether the statement belongs to the group, tables of the same name in different schemas are apart
+func (g Group) Holds(stmt Statement) bool {
+	return g.Schema == stmt.Schema && g.Table == stmt.Table
+}
+
+// Name is the table of the group, qualified by its schema when the statements qualify it
+func (g Group) Name() string {
+	if g.Schema == "" {
+		return g.Table
+	}
+	return g.Schema + "." + g.Table
+}
+
+// GroupByTable groups statements per table by order of appearance. Statements of a group keep their relative order,
+// but the groups do not keep the order across tables, which must be applied by the order of the statements
+func GroupByTable(statements []Statement) []Group {
+	var groups []Group
+	for _, stmt := range statements {
+		_, idx, ok := lo.FindIndexOf(groups, func(group Group) bool {
+			return group.Holds(stmt)
+		})
+		if !ok {
+			groups = append(groups, Group{Schema: stmt.Schema, Table: stmt.Table})
+			idx = len(groups) - 1
+		}
+		groups[idx].Statements = append(groups[idx].Statements, stmt)
+	}
+	return groups
+}
+
+// ParseStatements splits the sql planned by atlas into statements, by their order in the plan
+func ParseStatements(sql string) []Statement {
+	var statements []Statement
+	var comment []string
+	var current []string
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" && len(current) == 0:
+			continue
+		case strings.HasPrefix(trimmed, "--") && len(current) == 0:
+			comment = append(comment, strings.TrimSpace(strings.TrimPrefix(trimmed, "--")))
+			continue
+		}
+		current = append(current, line)
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, newStatement(comment, current))
+			comment, current = nil, nil
+		}
+	}
+	if len(current) > 0 {
+		statements = append(statements, newStatement(comment, current))
+	}
+	return statements
+}
+
+func newStatement(comment, lines []string) Statement {
+	stmt := Statement{
+		Comment: strings.Join(comment, " "),
+		SQL:     strings.TrimSpace(strings.Join(lines, "\n")),
+	}
+	if tables := statementTables(stmt.Comment, stmt.SQL); len(tables) > 0 {
+		stmt.Schema, stmt.Table = tables[0].schema, tables[0].table
+	}
+	return stmt
+}
+
+type qualifiedTable struct {
+	schema string
+	table  string
+}
+
+// statementTables lists the tables changed by a statement as described by its sql,
+// or else the table named by the comment atlas gave it, e.g. when dropping an index in postgres
+func statementTables(comment, sql string) []qualifiedTable {
+	var tables []qualifiedTable
+	for _, change := range Describe(sql) {
+		table := qualifiedTable{schema: change.Schema, table: change.Table}
+		if table.table != "" && !lo.Contains(tables, table) {
+			tables = append(tables, table)
+		}
+	}
+	if len(tables) > 0 {
+		return tables
+	}
+	for _, pattern := range commentTablePatterns {
+		if match := pattern.FindStringSubmatch(comment); match != nil {
+			return []qualifiedTable{{table: match[1]}}
+		}
+	}
+	return nil
+}
+
+// Format renders statements the way atlas writes them into migration files
+func Format(statements []Statement) string {
+	sb := strings.Builder{}
+	for _, stmt := range statements {
+		if stmt.Comment != "" {
+			sb.WriteString("-- ")
+			sb.WriteString(stmt.Comment)
+			sb.WriteString("\n")
+		}
+		sb.WriteString(stmt.SQL)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// DirPath resolves the local path of a migration dir url
+func DirPath(dirURL string) (string, error) {
+	if dirURL == "" {
+		dirURL = DefaultDirURL
+	}
+	dir, ok := strings.CutPrefix(dirURL, "file://")
+	if !ok {
+		return "", fmt.Errorf("unsupported migration dir url '%s', only file:// urls are supported", dirURL)
+	}
+	// atlas allows query params on dir urls, e.g. file://migrations?format=golang-migrate
+	dir, _, _ = strings.Cut(dir, "?")
+	return dir, nil
+}
+
+// WriteFile writes a new versioned migration file named after the given name into the migration dir.
+// The version is bumped past any file holding the current one, as versions have a one second resolution.
+// The dir's atlas.sum must be re-hashed afterwards for atlas to accept the new file.
+func WriteFile(dirURL, name, sql string) (string, error) {
+	dir, err := DirPath(dirURL)
+	if err != nil {
+		return "", err
+	}
+	if format := dirFormat(dirURL); format != "" && format != "atlas" {
+		return "", fmt.Errorf("unsupported migration dir format '%s', only atlas formatted dirs can be written to", format)
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create migration dir: %w", err)
+	}
+	now := time.Now().UTC()
+	for i := 0; i < maxVersionBumps; i++ {
+		version := now.Add(time.Duration(i) * time.Second).Format(versionLayout)
+		if taken, _ := filepath.Glob(filepath.Join(dir, version+"_*.sql")); len(taken) > 0 {
+			continue
+		}
+		fpath := filepath.Join(dir, fmt.Sprintf("%s_%s.sql", version, name))
+		f, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write migration file: %w", err)
+		}
+		_, err = f.WriteString(sql)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write migration file: %w", err)
+		}
+		return fpath, nil
+	}
+	return "", fmt.Errorf("failed to write migration file: versions %s and the %d seconds after it are taken", now.Format(versionLayout), maxVersionBumps-1)
+}
+
+// dirFormat is the format set on a migration dir url, atlas assumes its own format when it is not set
+func dirFormat(dirURL string) string {
+	u, err := url.Parse(dirURL)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("format")
+}
@@ -0,0 +1,170 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroupByTable(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []Group
+	}{
+		{
+			name: "empty",
+			sql:  "\n  \n",
+		},
+		{
+			name: "tables from comments",
+			sql: `-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "age" integer NULL;
+-- Create index "orders_user" to table: "orders"
+CREATE INDEX "orders_user" ON "orders" ("user_id");
+-- Modify "users" table
+ALTER TABLE "users" DROP COLUMN "bio";
+`,
+			want: []Group{
+				{Table: "users", Statements: []Statement{
+					{Comment: `Modify "users" table`, SQL: `ALTER TABLE "users" ADD COLUMN "age" integer NULL;`, Table: "users"},
+					{Comment: `Modify "users" table`, SQL: `ALTER TABLE "users" DROP COLUMN "bio";`, Table: "users"},
+				}},
+				{Table: "orders", Statements: []Statement{
+					{Comment: `Create index "orders_user" to table: "orders"`, SQL: `CREATE INDEX "orders_user" ON "orders" ("user_id");`, Table: "orders"},
+				}},
+			},
+		},
+		{
+			name: "multi-line statement",
+			sql: `-- Create "orders" table
+CREATE TABLE "orders" (
+  "id" integer NOT NULL,
+  "note" text NULL,
+  PRIMARY KEY ("id")
+);
+`,
+			want: []Group{{Table: "orders", Statements: []Statement{{
+				Comment: `Create "orders" table`,
+				SQL:     "CREATE TABLE \"orders\" (\n  \"id\" integer NOT NULL,\n  \"note\" text NULL,\n  PRIMARY KEY (\"id\")\n);",
+				Table:   "orders",
+			}}}},
+		},
+		{
+			name: "tables from sql",
+			sql:  "ALTER TABLE `shop`.`carts` ADD COLUMN `total` int;\nCREATE INDEX IF NOT EXISTS idx ON shop.carts (total);",
+			want: []Group{{Schema: "shop", Table: "carts", Statements: []Statement{
+				{SQL: "ALTER TABLE `shop`.`carts` ADD COLUMN `total` int;", Schema: "shop", Table: "carts"},
+				{SQL: "CREATE INDEX IF NOT EXISTS idx ON shop.carts (total);", Schema: "shop", Table: "carts"},
+			}}},
+		},
+		{
+			name: "same table in different schemas",
+			sql:  "-- Modify \"users\" table\nALTER TABLE \"a\".\"users\" ADD COLUMN \"age\" integer;\n-- Modify \"users\" table\nALTER TABLE \"b\".\"users\" DROP COLUMN \"bio\";",
+			want: []Group{
+				{Schema: "a", Table: "users", Statements: []Statement{
+					{Comment: `Modify "users" table`, SQL: `ALTER TABLE "a"."users" ADD COLUMN "age" integer;`, Schema: "a", Table: "users"},
+				}},
+				{Schema: "b", Table: "users", Statements: []Statement{
+					{Comment: `Modify "users" table`, SQL: `ALTER TABLE "b"."users" DROP COLUMN "bio";`, Schema: "b", Table: "users"},
+				}},
+			},
+		},
+		{
+			name: "tables named by the sql, or else by the comment",
+			sql:  "-- Modify \"order items\" table\nALTER TABLE \"order items\" ADD COLUMN \"qty\" integer;\n-- Drop index \"idx\" from table: \"carts\"\nDROP INDEX \"idx\";",
+			want: []Group{
+				{Table: "order items", Statements: []Statement{
+					{Comment: `Modify "order items" table`, SQL: `ALTER TABLE "order items" ADD COLUMN "qty" integer;`, Table: "order items"},
+				}},
+				{Table: "carts", Statements: []Statement{
+					{Comment: `Drop index "idx" from table: "carts"`, SQL: `DROP INDEX "idx";`, Table: "carts"},
+				}},
+			},
+		},
+		{
+			name: "not tied to a table",
+			sql: `-- Add new schema named "audit"
+CREATE SCHEMA "audit";
+-- Create extension "pgcrypto"
+CREATE EXTENSION "pgcrypto";
+-- Create "events" table
+CREATE TABLE "audit"."events" ("id" bigint NOT NULL);
+`,
+			want: []Group{
+				{Statements: []Statement{
+					{Comment: `Add new schema named "audit"`, SQL: `CREATE SCHEMA "audit";`},
+					{Comment: `Create extension "pgcrypto"`, SQL: `CREATE EXTENSION "pgcrypto";`},
+				}},
+				{Schema: "audit", Table: "events", Statements: []Statement{
+					{Comment: `Create "events" table`, SQL: `CREATE TABLE "audit"."events" ("id" bigint NOT NULL);`, Schema: "audit", Table: "events"},
+				}},
+			},
+		},
+		{
+			name: "unterminated statement",
+			sql:  "-- Drop \"carts\" table\nDROP TABLE \"carts\"",
+			want: []Group{{Table: "carts", Statements: []Statement{
+				{Comment: `Drop "carts" table`, SQL: `DROP TABLE "carts"`, Table: "carts"},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupByTable(ParseStatements(tt.sql)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupByTable()\ngot:  %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	dirURL := "file://" + dir + "?format=atlas"
+	var written []string
+	// versions have a one second resolution, writing twice in a row must not overwrite the first file
+	for _, sql := range []string{"CREATE TABLE a (id int);\n", "CREATE TABLE b (id int);\n"} {
+		fpath, err := WriteFile(dirURL, "compare", sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := os.ReadFile(fpath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(raw) != sql {
+			t.Errorf("%s holds %q, want %q", fpath, raw, sql)
+		}
+		written = append(written, fpath)
+	}
+	if written[0] == written[1] {
+		t.Fatalf("both migrations were written to %s", written[0])
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d files, want 2", len(entries))
+	}
+	versions := make(map[string]bool)
+	for _, fpath := range written {
+		version, _, _ := strings.Cut(filepath.Base(fpath), "_")
+		versions[version] = true
+	}
+	if len(versions) != 2 {
+		t.Errorf("migrations share a version: %v", written)
+	}
+}
+
+func TestWriteFileRejectsOtherFormats(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := WriteFile("file://"+dir+"?format=golang-migrate", "compare", "CREATE TABLE a (id int);"); err == nil || !strings.Contains(err.Error(), "golang-migrate") {
+		t.Errorf("expected an error naming the format, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+		t.Errorf("got %d files, want none", len(entries))
+	}
+}
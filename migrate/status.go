@@ -0,0 +1,95 @@
+package migrate
+
+import (
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"fmt"
+	"github.com/samber/lo"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Status is the state of a migration dir against a database, as reported by 'atlas migrate status'
+type Status struct {
+	DirURL  string
+	Current string
+	Next    string
+	State   string
+	Error   string
+	Files   []File
+}
+
+// File is a migration file of the dir, along with its revision when it was applied
+type File struct {
+	Name          string
+	Version       string
+	Description   string
+	Applied       bool
+	AppliedStmts  int
+	TotalStmts    int
+	ExecutedAt    time.Time
+	ExecutionTime time.Duration
+	Error         string
+}
+
+// Partial is true when applying the file failed midway
+func (f File) Partial() bool {
+	return f.Applied && f.TotalStmts > 0 && f.AppliedStmts < f.TotalStmts
+}
+
+// NewStatus matches the files of the dir with their applied revisions
+func NewStatus(dirURL string, status *atlasexec.MigrateStatus) *Status {
+	s := &Status{
+		DirURL:  dirURL,
+		Current: status.Current,
+		Next:    status.Next,
+		State:   status.Status,
+		Error:   status.Error,
+	}
+	revisions := lo.KeyBy(status.Applied, func(rev *atlasexec.Revision) string {
+		return rev.Version
+	})
+	for _, available := range status.Available {
+		file := File{
+			Name:        available.Name,
+			Version:     available.Version,
+			Description: available.Description,
+		}
+		if rev, ok := revisions[available.Version]; ok {
+			file.Applied = true
+			file.AppliedStmts = rev.Applied
+			file.TotalStmts = rev.Total
+			file.ExecutedAt = rev.ExecutedAt
+			file.ExecutionTime = rev.ExecutionTime
+			file.Error = rev.Error
+		}
+		s.Files = append(s.Files, file)
+	}
+	return s
+}
+
+// ReadFile reads the sql of a migration file, the dir must be local
+func (s *Status) ReadFile(file File) (string, error) {
+	dir, err := DirPath(s.DirURL)
+	if err != nil {
+		return "", err
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, file.Name))
+	if err != nil {
+		return "", fmt.Errorf("failed to read migration file: %w", err)
+	}
+	return string(raw), nil
+}
+
+// Tables lists the tables touched by the given sql, by order of appearance
+func Tables(sql string) []string {
+	var tables []string
+	for _, stmt := range ParseStatements(sql) {
+		for _, table := range statementTables(stmt.Comment, stmt.SQL) {
+			if name := table.table; !lo.Contains(tables, name) {
+				tables = append(tables, name)
+			}
+		}
+	}
+	return tables
+}
@@ -0,0 +1,223 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/samber/lo"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	"os"
+	"strings"
+)
+
+// DefaultConfigURL is where atlas looks for the project file when --config is not set
+const DefaultConfigURL = "file://atlas.hcl"
+
+// Env is an environment declared in an atlas project file.
+// Values are evaluated on a best effort basis, atlas itself resolves them when inspecting.
+// Values that cannot be evaluated, e.g. of data sources, hold their source text for display and are listed in Unresolved.
+type Env struct {
+	Name         string
+	URL          string
+	DevURL       string
+	Schemas      []string
+	Exclude      []string
+	MigrationDir string
+	// Unresolved are the names of the attributes that could not be evaluated, e.g. "url" or "dir"
+	Unresolved []string
+}
+
+// Resolved tells whether the attribute was evaluated, so its value can be used and not only displayed
+func (e Env) Resolved(attr string) bool {
+	return !lo.Contains(e.Unresolved, attr)
+}
+
+// Require fails when any of the attributes could not be evaluated
+func (e Env) Require(attrs ...string) error {
+	if unresolved := lo.Filter(attrs, func(attr string, _ int) bool { return !e.Resolved(attr) }); len(unresolved) > 0 {
+		return fmt.Errorf("atlastui cannot evaluate the %s of env '%s'", strings.Join(unresolved, ", "), e.Name)
+	}
+	return nil
+}
+
+type Project struct {
+	Path string
+	Envs []Env
+}
+
+// Load parses the project file at the given config url, vars override variable defaults as with --var.
+// Returns nil without an error when the default project file does not exist.
+func Load(configURL string, vars map[string]string) (*Project, error) {
+	isDefault := configURL == ""
+	if isDefault {
+		configURL = DefaultConfigURL
+	}
+	fpath, ok := strings.CutPrefix(configURL, "file://")
+	if !ok {
+		return nil, fmt.Errorf("unsupported config url '%s', only file:// urls are supported", configURL)
+	}
+	src, err := os.ReadFile(fpath)
+	if errors.Is(err, os.ErrNotExist) && isDefault {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project file at '%s': %w", fpath, err)
+	}
+	file, diags := hclsyntax.ParseConfig(src, fpath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse project file: %w", diags)
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unexpected project file structure")
+	}
+
+	p := &parser{src: src, ctx: &hcl.EvalContext{
+		Variables: make(map[string]cty.Value),
+		Functions: map[string]function.Function{
+			"getenv": getenvFunc,
+			"upper":  stdlib.UpperFunc,
+			"lower":  stdlib.LowerFunc,
+			"format": stdlib.FormatFunc,
+			"join":   stdlib.JoinFunc,
+			"split":  stdlib.SplitFunc,
+		},
+	}}
+	p.evalVariables(body, vars)
+	p.evalLocals(body)
+
+	proj := &Project{Path: fpath}
+	for _, block := range body.Blocks {
+		if block.Type != "env" {
+			continue
+		}
+		env := p.env(block)
+		if env.Name != "" {
+			proj.Envs = append(proj.Envs, env)
+		}
+	}
+	return proj, nil
+}
+
+func (p *Project) Env(name string) (Env, bool) {
+	for _, env := range p.Envs {
+		if env.Name == name {
+			return env, true
+		}
+	}
+	return Env{}, false
+}
+
+type parser struct {
+	src []byte
+	ctx *hcl.EvalContext
+}
+
+func (p *parser) evalVariables(body *hclsyntax.Body, overrides map[string]string) {
+	vars := make(map[string]cty.Value)
+	for _, block := range body.Blocks {
+		if block.Type != "variable" || len(block.Labels) != 1 {
+			continue
+		}
+		name := block.Labels[0]
+		if override, ok := overrides[name]; ok {
+			vars[name] = cty.StringVal(override)
+		} else if attr, ok := block.Body.Attributes["default"]; ok {
+			if val, diags := attr.Expr.Value(p.ctx); !diags.HasErrors() {
+				vars[name] = val
+			}
+		}
+	}
+	p.ctx.Variables["var"] = cty.ObjectVal(vars)
+}
+
+func (p *parser) evalLocals(body *hclsyntax.Body) {
+	locals := make(map[string]cty.Value)
+	for _, block := range body.Blocks {
+		if block.Type != "locals" {
+			continue
+		}
+		for name, attr := range block.Body.Attributes {
+			if val, diags := attr.Expr.Value(p.ctx); !diags.HasErrors() {
+				locals[name] = val
+			}
+		}
+	}
+	p.ctx.Variables["local"] = cty.ObjectVal(locals)
+}
+
+func (p *parser) env(block *hclsyntax.Block) Env {
+	env := Env{}
+	if len(block.Labels) > 0 {
+		env.Name = block.Labels[0]
+	} else if attr, ok := block.Body.Attributes["name"]; ok {
+		// dynamic envs (e.g. name = atlas.env) cannot be listed
+		if val, diags := attr.Expr.Value(p.ctx); !diags.HasErrors() && val.Type() == cty.String {
+			env.Name = val.AsString()
+		}
+	}
+	resolve := func(attr string, resolved bool) {
+		if !resolved {
+			env.Unresolved = append(env.Unresolved, attr)
+		}
+	}
+	var resolved bool
+	env.URL, resolved = p.stringAttr(block.Body, "url")
+	resolve("url", resolved)
+	env.DevURL, resolved = p.stringAttr(block.Body, "dev")
+	resolve("dev", resolved)
+	env.Schemas, resolved = p.listAttr(block.Body, "schemas")
+	resolve("schemas", resolved)
+	env.Exclude, resolved = p.listAttr(block.Body, "exclude")
+	resolve("exclude", resolved)
+	for _, inner := range block.Body.Blocks {
+		if inner.Type == "migration" {
+			env.MigrationDir, resolved = p.stringAttr(inner.Body, "dir")
+			resolve("dir", resolved)
+		}
+	}
+	return env
+}
+
+// stringAttr evaluates the attribute, falling back to its source text when it cannot be evaluated, which is not resolved
+func (p *parser) stringAttr(body *hclsyntax.Body, name string) (string, bool) {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return "", true
+	}
+	val, diags := attr.Expr.Value(p.ctx)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || val.Type() != cty.String {
+		return string(attr.Expr.Range().SliceBytes(p.src)), false
+	}
+	return val.AsString(), true
+}
+
+func (p *parser) listAttr(body *hclsyntax.Body, name string) ([]string, bool) {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return nil, true
+	}
+	val, diags := attr.Expr.Value(p.ctx)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || !val.CanIterateElements() {
+		return []string{string(attr.Expr.Range().SliceBytes(p.src))}, false
+	}
+	var values []string
+	for it := val.ElementIterator(); it.Next(); {
+		_, elem := it.Element()
+		if elem.Type() == cty.String {
+			values = append(values, elem.AsString())
+		}
+	}
+	return values, true
+}
+
+var getenvFunc = function.New(&function.Spec{
+	Params: []function.Parameter{{Name: "key", Type: cty.String}},
+	Type:   function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		return cty.StringVal(os.Getenv(args[0].AsString())), nil
+	},
+})
@@ -0,0 +1,283 @@
+package snapshot
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/project"
+	"github.com/samber/lo"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	idLayout       = "20060102T150405.000Z"
+	sourceFileName = "source"
+	fileExt        = ".json"
+	redacted       = "xxxxx"
+	// maxSnapshots is the number of snapshots kept per source, older ones are removed on save
+	maxSnapshots = 50
+)
+
+// credentialParams are url query parameters redacted along with the url password
+var credentialParams = []string{"password", "pass", "pwd", "token", "secret", "sslpassword", "access_token"}
+
+type Snapshot struct {
+	TakenAt time.Time    `json:"taken_at"`
+	Source  string       `json:"source"`
+	Data    inspect.Data `json:"data"`
+}
+
+// Entry describes a stored snapshot without loading its data
+type Entry struct {
+	ID      string
+	Path    string
+	Source  string
+	TakenAt time.Time
+}
+
+// Store keeps snapshots on disk, grouped in a directory per source
+type Store struct {
+	dir string
+}
+
+func NewStore() (*Store, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return &Store{dir: filepath.Join(cacheDir, "atlastui", "snapshots")}, nil
+}
+
+// SourceOf describes where the params inspect from, with any credentials redacted.
+// Returns empty string for offline inputs, which are not worth a snapshot.
+func SourceOf(params *inspect.Params) string {
+	switch {
+	case params.FromFilePath != "":
+		return ""
+	case params.URL != "":
+		return RedactURL(params.URL)
+	case params.Env != "":
+		// env names repeat across projects, e.g. dev and prod, so the project file tells them apart
+		return fmt.Sprintf("env://%s?config=%s", params.Env, projectURL(params.ConfigURL))
+	default:
+		return ""
+	}
+}
+
+// projectURL is the project file url of --config, made absolute as it is relative to the working directory
+func projectURL(configURL string) string {
+	configURL = cmp.Or(configURL, project.DefaultConfigURL)
+	fpath, ok := strings.CutPrefix(configURL, "file://")
+	if !ok {
+		return RedactURL(configURL)
+	}
+	if abs, err := filepath.Abs(fpath); err == nil {
+		fpath = abs
+	}
+	return "file://" + filepath.ToSlash(fpath)
+}
+
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		// never risk leaking an unparsable url, yet keep unrelated urls apart
+		return "redacted-" + hashOf(raw)
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	query := u.Query()
+	for key := range query {
+		if lo.Contains(credentialParams, strings.ToLower(key)) {
+			query.Set(key, redacted)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// Save stores a snapshot of the data, unless it equals the latest snapshot of the source which is returned instead.
+// Only the newest snapshots of the source are kept.
+func (s *Store) Save(source string, data inspect.Data) (*Entry, error) {
+	entries, err := s.List(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		same, err := s.equals(entries[0], data)
+		if err != nil {
+			return nil, err
+		}
+		if same {
+			return &entries[0], nil
+		}
+	}
+	snap := Snapshot{
+		TakenAt: time.Now().UTC(),
+		Source:  source,
+		Data:    data,
+	}
+	dir := s.sourceDir(source)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshots directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, sourceFileName), []byte(source), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot source: %w", err)
+	}
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	entry := Entry{
+		ID:      snap.TakenAt.Format(idLayout),
+		Source:  source,
+		TakenAt: snap.TakenAt,
+	}
+	entry.Path = filepath.Join(dir, entry.ID+fileExt)
+	if err = os.WriteFile(entry.Path, raw, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	// the new snapshot is not listed yet, so one less of the listed is kept
+	for _, old := range entries[min(len(entries), maxSnapshots-1):] {
+		if err = os.Remove(old.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return &entry, fmt.Errorf("failed to remove old snapshot: %w", err)
+		}
+	}
+	return &entry, nil
+}
+
+// equals tells whether the snapshot holds the given data
+func (s *Store) equals(entry Entry, data inspect.Data) (bool, error) {
+	snap, err := s.Load(entry.Path)
+	if err != nil {
+		return false, err
+	}
+	stored, err := json.Marshal(snap.Data)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	current, err := json.Marshal(data)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	return bytes.Equal(stored, current), nil
+}
+
+// List returns the snapshots of the given source, newest first
+func (s *Store) List(source string) ([]Entry, error) {
+	entries, err := s.listDir(s.sourceDir(source))
+	if err != nil {
+		return nil, err
+	}
+	sortNewestFirst(entries)
+	return entries, nil
+}
+
+// ListAll returns the snapshots of all sources, newest first
+func (s *Store) ListAll() ([]Entry, error) {
+	dirs, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots directory: %w", err)
+	}
+	var entries []Entry
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		dirEntries, err := s.listDir(filepath.Join(s.dir, dir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, dirEntries...)
+	}
+	sortNewestFirst(entries)
+	return entries, nil
+}
+
+// Load reads a snapshot by its id, or by a path to a snapshot file
+func (s *Store) Load(ref string) (*Snapshot, error) {
+	fpath := ref
+	if !strings.HasSuffix(ref, fileExt) {
+		entries, err := s.ListAll()
+		if err != nil {
+			return nil, err
+		}
+		entry, ok := lo.Find(entries, func(entry Entry) bool {
+			return entry.ID == ref
+		})
+		if !ok {
+			return nil, fmt.Errorf("snapshot '%s' not found", ref)
+		}
+		fpath = entry.Path
+	}
+	raw, err := os.ReadFile(fpath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot at '%s': %w", fpath, err)
+	}
+	var snap Snapshot
+	if err = json.Unmarshal(raw, &snap); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot at '%s': %w", fpath, err)
+	}
+	return &snap, nil
+}
+
+func (s *Store) listDir(dir string) ([]Entry, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots directory: %w", err)
+	}
+	source, err := os.ReadFile(filepath.Join(dir, sourceFileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read snapshot source: %w", err)
+	}
+	entries := make([]Entry, 0, len(files))
+	for _, file := range files {
+		id, ok := strings.CutSuffix(file.Name(), fileExt)
+		if !ok || file.IsDir() {
+			continue
+		}
+		takenAt, err := time.Parse(idLayout, id)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{
+			ID:      id,
+			Path:    filepath.Join(dir, file.Name()),
+			Source:  string(source),
+			TakenAt: takenAt,
+		})
+	}
+	return entries, nil
+}
+
+func (s *Store) sourceDir(source string) string {
+	return filepath.Join(s.dir, hashOf(source))
+}
+
+// hashOf is a short hex digest of the text, telling texts apart without revealing them
+func hashOf(text string) string {
+	hash := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+func sortNewestFirst(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].TakenAt.After(entries[j].TakenAt)
+	})
+}
@@ -0,0 +1,30 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// mysqlQuery reads the estimates of information_schema, mysql keeps no analyze or vacuum times
+const mysqlQuery = `
+SELECT TABLE_SCHEMA, TABLE_NAME, COALESCE(TABLE_ROWS, -1), COALESCE(DATA_LENGTH, -1), COALESCE(INDEX_LENGTH, -1)
+FROM information_schema.TABLES
+WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA IN (%s)`
+
+type mysqlProvider struct {
+	url string
+}
+
+func (p *mysqlProvider) Stats(ctx context.Context, schemas []string) ([]Table, error) {
+	args := make([]any, 0, len(schemas))
+	for _, schema := range schemas {
+		args = append(args, schema)
+	}
+	query := strings.Replace(mysqlQuery, "%s", strings.TrimSuffix(strings.Repeat("?, ", len(schemas)), ", "), 1)
+	return queryTables(ctx, p.url, query, args, func(rows *sql.Rows) (Table, error) {
+		var t Table
+		err := rows.Scan(&t.Schema, &t.Name, &t.Rows, &t.DataSize, &t.IndexSize)
+		return t, err
+	})
+}
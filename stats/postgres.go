@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"github.com/lib/pq"
+)
+
+// postgresQuery reads the planner estimates, which are as fresh as the last analyze
+const postgresQuery = `
+SELECT n.nspname, c.relname, c.reltuples::bigint, pg_table_size(c.oid), pg_indexes_size(c.oid),
+       GREATEST(s.last_analyze, s.last_autoanalyze), GREATEST(s.last_vacuum, s.last_autovacuum)
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+WHERE c.relkind IN ('r', 'p') AND n.nspname = ANY($1)`
+
+type postgresProvider struct {
+	url string
+}
+
+func (p *postgresProvider) Stats(ctx context.Context, schemas []string) ([]Table, error) {
+	return queryTables(ctx, p.url, postgresQuery, []any{pq.Array(schemas)}, func(rows *sql.Rows) (Table, error) {
+		var t Table
+		var lastAnalyze, lastVacuum sql.NullTime
+		if err := rows.Scan(&t.Schema, &t.Name, &t.Rows, &t.DataSize, &t.IndexSize, &lastAnalyze, &lastVacuum); err != nil {
+			return t, err
+		}
+		// tables that were never analyzed have no estimate
+		if t.Rows < 0 {
+			t.Rows = Unknown
+		}
+		t.LastAnalyze = lastAnalyze.Time
+		t.LastVacuum = lastVacuum.Time
+		return t, nil
+	})
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"github.com/reallyliri/atlastui/db"
+)
+
+const (
+	sqliteSchema      = "main"
+	sqliteTablesQuery = `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`
+	// dbstat is only available in sqlite builds with SQLITE_ENABLE_DBSTAT_VTAB
+	sqliteSizesQuery = `
+SELECT m.tbl_name, SUM(CASE WHEN m.type = 'table' THEN s.pgsize ELSE 0 END), SUM(CASE WHEN m.type = 'index' THEN s.pgsize ELSE 0 END)
+FROM dbstat s JOIN sqlite_master m ON m.name = s.name
+GROUP BY m.tbl_name`
+)
+
+// sqliteProvider estimates the rows by the span of the rowids, as sqlite keeps no estimates and counting reads every row
+type sqliteProvider struct {
+	url string
+}
+
+func (p *sqliteProvider) Stats(ctx context.Context, schemas []string) ([]Table, error) {
+	conn, _, err := db.Open(p.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect for table statistics: %w", err)
+	}
+	defer conn.Close()
+	schema := sqliteSchema
+	if len(schemas) > 0 {
+		// a sqlite database has a single schema, the one atlas inspected
+		schema = schemas[0]
+	}
+
+	rows, err := conn.QueryContext(ctx, sqliteTablesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table statistics: %w", err)
+	}
+	var tables []Table
+	for rows.Next() {
+		t := Table{Schema: schema, DataSize: Unknown, IndexSize: Unknown}
+		if err = rows.Scan(&t.Name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read table statistics: %w", err)
+		}
+		tables = append(tables, t)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read table statistics: %w", err)
+	}
+
+	for i, t := range tables {
+		// tables without rowid have no estimate
+		tables[i].Rows = db.EstimateRows(ctx, conn, db.SQLite, schema, t.Name)
+	}
+
+	sizes, err := conn.QueryContext(ctx, sqliteSizesQuery)
+	if err != nil {
+		// sizes are optional
+		return tables, nil
+	}
+	defer sizes.Close()
+	byName := make(map[string]*Table, len(tables))
+	for i := range tables {
+		byName[tables[i].Name] = &tables[i]
+	}
+	for sizes.Next() {
+		var name string
+		var dataSize, indexSize int64
+		if err = sizes.Scan(&name, &dataSize, &indexSize); err != nil {
+			return nil, fmt.Errorf("failed to read table sizes: %w", err)
+		}
+		if t, ok := byName[name]; ok {
+			t.DataSize, t.IndexSize = dataSize, indexSize
+		}
+	}
+	return tables, sizes.Err()
+}
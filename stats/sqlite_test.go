@@ -0,0 +1,45 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteStats(t *testing.T) {
+	fpath := filepath.Join(t.TempDir(), "test.db")
+	conn, err := sql.Open("sqlite", fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, err = conn.Exec(`
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE empty (id INTEGER PRIMARY KEY);
+		CREATE TABLE tags (name TEXT PRIMARY KEY) WITHOUT ROWID;
+		INSERT INTO users (id, name) VALUES (1, 'a'), (2, 'b'), (5, 'c');
+		INSERT INTO tags VALUES ('x');
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := NewProvider("sqlite://" + fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables, err := provider.Stats(context.Background(), []string{"main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// rows are estimated by the span of the rowids, deleted rows included
+	want := map[string]int64{"empty": 0, "tags": Unknown, "users": 5}
+	if len(tables) != len(want) {
+		t.Fatalf("got %d tables, want %d", len(tables), len(want))
+	}
+	for _, table := range tables {
+		if table.Rows != want[table.Name] || table.Exact {
+			t.Errorf("%s has %d rows, exact %v, want an estimate of %d", table.Name, table.Rows, table.Exact, want[table.Name])
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package stats
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/reallyliri/atlastui/db"
+	"time"
+)
+
+// Unknown marks statistics the database does not provide, e.g. sizes in sqlite builds without dbstat
+const Unknown = -1
+
+// Table holds the statistics of a single table
+type Table struct {
+	Schema string
+	Name   string
+	// Rows is an estimate, unless Exact is set
+	Rows        int64
+	Exact       bool
+	DataSize    int64
+	IndexSize   int64
+	LastAnalyze time.Time
+	LastVacuum  time.Time
+}
+
+// TotalSize is the size of the table along with its indexes, or Unknown
+func (t Table) TotalSize() int64 {
+	if t.DataSize == Unknown || t.IndexSize == Unknown {
+		return Unknown
+	}
+	return t.DataSize + t.IndexSize
+}
+
+// Provider fetches table statistics of a database, it should be cheap enough to run on every inspection
+type Provider interface {
+	Stats(ctx context.Context, schemas []string) ([]Table, error)
+}
+
+// NewProvider returns the stats provider of the database of the given atlas url
+func NewProvider(dbURL string) (Provider, error) {
+	dialect, _, err := db.Parse(dbURL)
+	if err != nil {
+		return nil, err
+	}
+	switch dialect {
+	case db.Postgres:
+		return &postgresProvider{url: dbURL}, nil
+	case db.MySQL:
+		return &mysqlProvider{url: dbURL}, nil
+	case db.SQLite:
+		return &sqliteProvider{url: dbURL}, nil
+	default:
+		return nil, fmt.Errorf("table statistics are not supported for %s databases", dialect)
+	}
+}
+
+// queryTables opens a short-lived connection, and scans the tables returned by the query
+func queryTables(ctx context.Context, dbURL, query string, args []any, scan func(rows *sql.Rows) (Table, error)) ([]Table, error) {
+	conn, _, err := db.Open(dbURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect for table statistics: %w", err)
+	}
+	defer conn.Close()
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table statistics: %w", err)
+	}
+	defer rows.Close()
+	var tables []Table
+	for rows.Next() {
+		table, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read table statistics: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
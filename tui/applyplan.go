@@ -0,0 +1,304 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/migrate"
+	"github.com/reallyliri/atlastui/tui/format"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"strings"
+	"time"
+)
+
+const warningMarker = "⚠"
+
+// ApplyPlanLoader runs 'atlas schema apply' in dry-run mode, returning the pending statements
+type ApplyPlanLoader func(ctx context.Context) ([]string, error)
+
+type plannedTable struct {
+	schema  string
+	table   string
+	change  inspect.Change
+	changes []migrate.Change
+}
+
+func (t plannedTable) warnings() int {
+	return lo.CountBy(t.changes, func(change migrate.Change) bool {
+		return change.Warning != ""
+	})
+}
+
+// applyPlan is a dry-run of applying a desired schema onto the inspected database
+type applyPlan struct {
+	load       ApplyPlanLoader
+	cancel     context.CancelFunc
+	statements []string
+	loaded     bool
+	err        error
+	startedAt  time.Time
+	tables     []plannedTable
+	cursor     int
+	details    viewport.Model
+}
+
+type applyPlanLoadedMsg struct {
+	statements []string
+	err        error
+}
+
+// WithApplyPlan previews the changes of applying a schema, on top of the inspected schema
+func WithApplyPlan(load ApplyPlanLoader) Option {
+	return func(c *runConfig) {
+		c.applyPlan = load
+	}
+}
+
+func (m *model) startApplyPlan() tea.Cmd {
+	p := m.applyPlan
+	ctx, cancel := context.WithCancel(m.ctx)
+	p.cancel = cancel
+	p.startedAt = time.Now()
+	m.state.overlay = types.ApplyPlanOverlay
+	return func() tea.Msg {
+		defer cancel()
+		statements, err := p.load(ctx)
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("cancelled after %s", formatElapsed(time.Since(p.startedAt)))
+		}
+		return applyPlanLoadedMsg{statements: statements, err: err}
+	}
+}
+
+func (m *model) onApplyPlanLoaded(msg applyPlanLoadedMsg) {
+	p := m.applyPlan
+	p.cancel = nil
+	p.loaded = true
+	p.err = msg.err
+	p.statements = msg.statements
+	p.details = viewport.New(0, 0)
+	m.applyPlanDiff()
+}
+
+// applyPlanDiff groups the planned changes per table, and highlights them like a diff once both the plan and the schema are loaded
+func (m *model) applyPlanDiff() {
+	p := m.applyPlan
+	if p == nil || !p.loaded || p.err != nil || !m.loaded() {
+		return
+	}
+	p.tables = nil
+	for _, stmt := range p.statements {
+		for _, change := range migrate.Describe(stmt) {
+			m.addPlannedChange(change)
+		}
+	}
+
+	diff := inspect.Diff{Schemas: make(map[string]*inspect.SchemaDiff)}
+	for _, t := range p.tables {
+		if t.table == "" {
+			continue
+		}
+		schemaDiff, ok := diff.Schemas[t.schema]
+		if !ok {
+			schemaDiff = &inspect.SchemaDiff{Change: inspect.Modified, Tables: make(map[string]*inspect.TableDiff)}
+			diff.Schemas[t.schema] = schemaDiff
+		}
+		tableDiff := &inspect.TableDiff{
+			Change:      t.change,
+			Columns:     make(map[string]inspect.Change),
+			Indexes:     make(map[string]inspect.Change),
+			ForeignKeys: make(map[string]inspect.Change),
+		}
+		for _, change := range t.changes {
+			switch change.Kind {
+			case migrate.AddColumn:
+				tableDiff.Columns[change.Object] = inspect.Added
+			case migrate.DropColumn:
+				tableDiff.Columns[change.Object] = inspect.Removed
+			case migrate.ModifyColumn, migrate.RenameColumn:
+				tableDiff.Columns[strings.SplitN(change.Object, " to ", 2)[0]] = inspect.Modified
+			case migrate.AddIndex:
+				tableDiff.Indexes[change.Object] = inspect.Added
+			case migrate.DropIndex:
+				tableDiff.Indexes[change.Object] = inspect.Removed
+			case migrate.AddForeignKey:
+				tableDiff.ForeignKeys[change.Object] = inspect.Added
+			case migrate.DropForeignKey:
+				tableDiff.ForeignKeys[change.Object] = inspect.Removed
+			}
+		}
+		schemaDiff.Tables[t.table] = tableDiff
+	}
+	m.diff = diff
+	m.state.diffTitle = fmt.Sprintf("apply plan (%s to review)", keymap.ApplyPlan.Help().Key)
+	m.setData(*m.data)
+	m.onApplyPlanTableSelected()
+}
+
+func (m *model) addPlannedChange(change migrate.Change) {
+	p := m.applyPlan
+	schema := lo.Ternary(change.Schema != "", change.Schema, m.state.selectedSchema)
+	table := change.Table
+	if table == "" && change.Kind == migrate.DropIndex {
+		// postgres drops indexes by name only
+		table = m.indexTable(schema, change.Object)
+	}
+	tableChange := inspect.Modified
+	switch change.Kind {
+	case migrate.CreateTable:
+		tableChange = inspect.Added
+	case migrate.DropTable:
+		tableChange = inspect.Removed
+	}
+	_, idx, ok := lo.FindIndexOf(p.tables, func(t plannedTable) bool {
+		return t.schema == schema && t.table == table
+	})
+	if !ok {
+		p.tables = append(p.tables, plannedTable{schema: schema, table: table, change: tableChange})
+		idx = len(p.tables) - 1
+	}
+	if tableChange != inspect.Modified {
+		p.tables[idx].change = tableChange
+	}
+	p.tables[idx].changes = append(p.tables[idx].changes, change)
+}
+
+func (m *model) indexTable(schema, index string) string {
+	for _, table := range m.schemasByName[schema].Tables {
+		if lo.ContainsBy(table.Indexes, func(idx inspect.Index) bool { return idx.Name == index }) {
+			return table.Name
+		}
+	}
+	return ""
+}
+
+func (m *model) openApplyPlan() {
+	if m.applyPlan == nil {
+		m.state.bannerErr = fmt.Errorf("no apply plan, run the plan command to preview one")
+		return
+	}
+	m.state.overlay = types.ApplyPlanOverlay
+}
+
+func (m *model) onApplyPlanTableSelected() {
+	p := m.applyPlan
+	if len(p.tables) == 0 {
+		return
+	}
+	lines := make([]string, 0)
+	changes := p.tables[p.cursor].changes
+	for i, change := range changes {
+		title := strings.TrimSpace(fmt.Sprintf("%s %s", change.Kind, change.Object))
+		lines = append(lines, lo.Ternary(change.Destructive(), styles.ErrorStyle.Render(title), styles.TitleStyle.Render(title)))
+		if change.Warning != "" {
+			lines = append(lines, styles.ErrorStyle.Render(fmt.Sprintf("  %s %s", warningMarker, change.Warning)))
+		}
+		// a statement may make several changes, show it once after the last of them
+		if i == len(changes)-1 || changes[i+1].SQL != change.SQL {
+			lines = append(lines, styles.SubTitleStyle.Render("  "+change.SQL), "")
+		}
+	}
+	p.details.SetContent(strings.Join(lines, "\n"))
+	p.details.GotoTop()
+}
+
+func (m *model) updateApplyPlan(msg tea.KeyMsg) tea.Cmd {
+	p := m.applyPlan
+	switch {
+	case p.cancel != nil && key.Matches(msg, keymap.Cancel):
+		p.cancel()
+	case key.Matches(msg, keymap.Back):
+		m.state.overlay = types.NoOverlay
+	case key.Matches(msg, keymap.Quit):
+		m.state.quitting = true
+		return tea.Quit
+	case len(p.tables) == 0:
+		// nothing to act on yet
+	case key.Matches(msg, keymap.Up):
+		p.cursor = lo.Clamp(p.cursor-1, 0, len(p.tables)-1)
+		m.onApplyPlanTableSelected()
+	case key.Matches(msg, keymap.Down):
+		p.cursor = lo.Clamp(p.cursor+1, 0, len(p.tables)-1)
+		m.onApplyPlanTableSelected()
+	case key.Matches(msg, keymap.Select):
+		t := p.tables[p.cursor]
+		if !m.selectTable(t.schema, t.table) {
+			m.state.bannerErr = fmt.Errorf("table %s does not exist yet", lo.Ternary(t.table == "", otherStatementsTitle, t.table))
+			return nil
+		}
+		m.state.overlay = types.NoOverlay
+	default:
+		var cmd tea.Cmd
+		p.details, cmd = p.details.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *model) applyPlanView(width, height int) string {
+	p := m.applyPlan
+	switch {
+	case p.err != nil:
+		return withBorder(centeredBox(width, height,
+			styles.ErrorStyle.Render("Failed to plan schema apply"), "", styles.ErrorStyle.Render(p.err.Error()),
+		), true)
+	case !m.loaded():
+		// the plan is shown on top of the inspected schema
+		return m.loadingView(width, height)
+	case !p.loaded:
+		return withBorder(centeredBox(width, height,
+			fmt.Sprintf("%s Planning schema apply... %s", m.vms.globe.View(), formatElapsed(time.Since(p.startedAt))),
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to cancel", keymap.Cancel.Help().Key)),
+		), true)
+	case len(p.tables) == 0:
+		return withBorder(centeredBox(width, height,
+			"The schema is synced, there is nothing to apply",
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to close", keymap.Back.Help().Key)),
+		), true)
+	}
+
+	changes := lo.Flatten(lo.Map(p.tables, func(t plannedTable, _ int) []migrate.Change { return t.changes }))
+	destructive := lo.CountBy(changes, func(change migrate.Change) bool { return change.Destructive() })
+	warnings := lo.CountBy(changes, func(change migrate.Change) bool { return change.Warning != "" })
+	summary := fmt.Sprintf("%d statements · %d changes in %d tables", len(p.statements), len(changes), len(p.tables))
+	if destructive > 0 {
+		summary += styles.ErrorStyle.Render(fmt.Sprintf(" · %s %d destructive", warningMarker, destructive))
+	}
+	if warnings > destructive {
+		summary += styles.ErrorStyle.Render(fmt.Sprintf(" · %d more warnings", warnings-destructive))
+	}
+	header := lipgloss.JoinVertical(lipgloss.Left,
+		styles.TitleStyle.Render("Schema apply plan (dry run)"),
+		styles.SubTitleStyle.Render(summary),
+	)
+	help := styles.SubTitleStyle.Render(fmt.Sprintf("%s show table · %s close", keymap.Select.Help().Key, keymap.Back.Help().Key))
+
+	bodyHeight := height - lipgloss.Height(header) - lipgloss.Height(help)
+	listWidth := width / 3
+	lines := lo.Map(p.tables, func(t plannedTable, i int) string {
+		line := fmt.Sprintf("%s%s (%d)", format.ChangeMarker(t.change), lo.Ternary(t.table == "", otherStatementsTitle, t.table), len(t.changes))
+		if t.warnings() > 0 {
+			line += " " + warningMarker
+		}
+		return lo.Ternary(i == p.cursor, styles.TitleStyle.Render("> "+line), "  "+line)
+	})
+	offset := lo.Max([]int{0, p.cursor - bodyHeight + 1})
+	lines = lines[offset:lo.Min([]int{len(lines), offset + bodyHeight})]
+	list := lipgloss.NewStyle().Width(listWidth).Height(bodyHeight).MaxHeight(bodyHeight).Render(strings.Join(lines, "\n"))
+
+	p.details.Width = width - listWidth - 1
+	p.details.Height = bodyHeight
+	body := lipgloss.JoinHorizontal(lipgloss.Top, list, " ", p.details.View())
+
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, header, body, help),
+	), true)
+}
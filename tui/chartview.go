@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	chart "github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reallyliri/atlastui/analyze"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/format"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"slices"
+	"strings"
+)
+
+// chartView is how a details chart is sorted and filtered, kept while browsing tables
+type chartView struct {
+	// sortBy is the index of the sorted column, or -1 for the inspection order
+	sortBy  int
+	reverse bool
+	filter  string
+}
+
+// detailRows are the rows of a details chart, along with the plain values they are sorted and filtered by
+type detailRows struct {
+	columns []chart.Column
+	rows    []chart.Row
+	values  [][]string
+	// shown are the indexes of the displayed rows, in display order
+	shown []int
+}
+
+func newChartViews() map[types.TableDetailsSection]*chartView {
+	return lo.SliceToMap(types.SchemaSections, func(section types.TableDetailsSection) (types.TableDetailsSection, *chartView) {
+		return section, &chartView{sortBy: -1}
+	})
+}
+
+// setDetailRows keeps the rows of the charts of the selected table, and displays them by the current views
+func (m *model) setDetailRows(t inspect.Table) {
+	coverage := analyze.IndexCoverage(t)
+	m.vms.details = map[types.TableDetailsSection]*detailRows{
+		types.ColumnsTable: {
+			columns: colsChartColumns,
+			rows:    m.vms.colsChart.Rows(),
+			values: lo.Map(t.Columns, func(col inspect.Column, _ int) []string {
+				return []string{col.Name, col.Type, format.Bool(col.Null), coverage[col.Name].String()}
+			}),
+		},
+		types.IndexesTable: {
+			columns: idxChartColumns,
+			rows:    m.vms.idxChart.Rows(),
+			values:  lo.Map(t.Indexes, func(idx inspect.Index, _ int) []string { return indexRow(idx) }),
+		},
+		types.ForeignKeysTable: {
+			columns: fksChartColumns,
+			rows:    m.vms.fksChart.Rows(),
+			values:  lo.Map(t.ForeignKeys, func(fk inspect.ForeignKey, _ int) []string { return foreignKeyRow(fk) }),
+		},
+	}
+	for _, section := range types.SchemaSections {
+		m.applyChartView(section)
+	}
+}
+
+func (m *model) detailsChart(section types.TableDetailsSection) *chart.Model {
+	switch section {
+	case types.ColumnsTable:
+		return &m.vms.colsChart
+	case types.IndexesTable:
+		return &m.vms.idxChart
+	case types.ForeignKeysTable:
+		return &m.vms.fksChart
+	default:
+		return nil
+	}
+}
+
+// applyChartView filters and sorts the rows of the chart, marking the sorted column in its header
+func (m *model) applyChartView(section types.TableDetailsSection) {
+	d, ok := m.vms.details[section]
+	if !ok {
+		return
+	}
+	v := m.chartViews[section]
+	titles := lo.Map(d.columns, func(col chart.Column, _ int) string { return col.Title })
+	d.shown = lo.Filter(lo.Range(len(d.rows)), func(i int, _ int) bool { return matchesFilter(v.filter, titles, d.values[i]) })
+	if v.sortBy >= 0 {
+		slices.SortStableFunc(d.shown, func(a, b int) int {
+			return lo.Ternary(v.reverse, -1, 1) * cmp.Compare(strings.ToLower(d.values[a][v.sortBy]), strings.ToLower(d.values[b][v.sortBy]))
+		})
+	}
+	columns := slices.Clone(d.columns)
+	if v.sortBy >= 0 {
+		columns[v.sortBy].Title += lo.Ternary(v.reverse, " ▼", " ▲")
+	}
+	c := m.detailsChart(section)
+	c.SetColumns(columns)
+	c.SetRows(lo.Map(d.shown, func(i int, _ int) chart.Row { return d.rows[i] }))
+	c.SetCursor(c.Cursor())
+}
+
+// matchesFilter requires every term of the filter to be found in a value, terms like "null:yes" look in the matching column only
+func matchesFilter(filter string, titles []string, values []string) bool {
+	for _, term := range strings.Fields(strings.ToLower(filter)) {
+		column, text, qualified := strings.Cut(term, ":")
+		qualified = qualified && lo.ContainsBy(titles, func(title string) bool { return strings.HasPrefix(strings.ToLower(title), column) })
+		if !qualified {
+			text = term
+		}
+		if !lo.SomeBy(lo.Range(len(values)), func(i int) bool {
+			return (!qualified || strings.HasPrefix(strings.ToLower(titles[i]), column)) && strings.Contains(strings.ToLower(values[i]), text)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// shownIndex is the index of the item displayed at the given row of the chart, e.g. of the column within its table
+func (m *model) shownIndex(section types.TableDetailsSection, row int) (int, bool) {
+	d, ok := m.vms.details[section]
+	if !ok || row < 0 || row >= len(d.shown) {
+		return 0, false
+	}
+	return d.shown[row], true
+}
+
+// focusDetailsRow moves the cursor of the chart to the given item, clearing the filter when it hides the item
+func (m *model) focusDetailsRow(section types.TableDetailsSection, idx int) {
+	d, ok := m.vms.details[section]
+	if !ok {
+		return
+	}
+	if !lo.Contains(d.shown, idx) {
+		m.chartViews[section].filter = ""
+		m.applyChartView(section)
+	}
+	m.detailsChart(section).SetCursor(lo.IndexOf(d.shown, idx))
+	m.state.focused = types.DetailsContentsFocused
+}
+
+// cycleChartSort sorts by each column ascending then descending, and back to the inspection order
+func (m *model) cycleChartSort() {
+	section := m.state.selectedTab
+	v := m.chartViews[section]
+	switch {
+	case v.sortBy < 0:
+		v.sortBy, v.reverse = 0, false
+	case !v.reverse:
+		v.reverse = true
+	case v.sortBy+1 < len(m.vms.details[section].columns):
+		v.sortBy, v.reverse = v.sortBy+1, false
+	default:
+		v.sortBy, v.reverse = -1, false
+	}
+	m.applyChartView(section)
+}
+
+func (m *model) startChartFilter() tea.Cmd {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "filter, e.g. created or null:yes"
+	input.SetValue(m.chartViews[m.state.selectedTab].filter)
+	input.CursorEnd()
+	m.vms.chartFilter = input
+	m.state.filtering = true
+	return m.vms.chartFilter.Focus()
+}
+
+// updateChartFilter filters the chart as the filter is typed, enter keeps the filter and esc clears it
+func (m *model) updateChartFilter(msg tea.KeyMsg) tea.Cmd {
+	section := m.state.selectedTab
+	// plain keys are typed into the filter, so bindings of letters do not apply here
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.state.filtering = false
+	case tea.KeyEsc:
+		m.state.filtering = false
+		m.chartViews[section].filter = ""
+		m.applyChartView(section)
+	case tea.KeyUp, tea.KeyDown:
+		c := m.detailsChart(section)
+		var cmd tea.Cmd
+		*c, cmd = c.Update(msg)
+		return cmd
+	default:
+		var cmd tea.Cmd
+		m.vms.chartFilter, cmd = m.vms.chartFilter.Update(msg)
+		m.chartViews[section].filter = m.vms.chartFilter.Value()
+		m.applyChartView(section)
+		return cmd
+	}
+	return nil
+}
+
+// chartViewSummary describes the sort and filter of the displayed chart, for the table header
+func (m *model) chartViewSummary() string {
+	section := m.state.selectedTab
+	d, ok := m.vms.details[section]
+	if !ok {
+		return ""
+	}
+	v := m.chartViews[section]
+	var parts []string
+	if v.sortBy >= 0 {
+		parts = append(parts, fmt.Sprintf("sorted by %s %s", d.columns[v.sortBy].Title, lo.Ternary(v.reverse, "▼", "▲")))
+	}
+	switch {
+	case m.state.filtering:
+		parts = append(parts, fmt.Sprintf("%s (%d of %d)", m.vms.chartFilter.View(), len(d.shown), len(d.rows)))
+	case v.filter != "":
+		parts = append(parts, fmt.Sprintf("filter \"%s\" (%d of %d)", v.filter, len(d.shown), len(d.rows)))
+	}
+	return strings.Join(parts, format.TabsSeparator)
+}
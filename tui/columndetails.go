@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/format"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"strings"
+)
+
+// columnLink leads to a row of the details of a table, e.g. to an index a column takes part in, or a foreign key referencing it
+type columnLink struct {
+	table tableKey
+	tab   types.TableDetailsSection
+	// row is the index of the index or foreign key within its table
+	row int
+}
+
+// columnDetails describes the selected column, along with the links to wherever it takes part
+type columnDetails struct {
+	key    columnKey
+	column inspect.Column
+	links  linkList
+	// liveDefault is the default read off the database, for sources not reporting defaults
+	liveDefault    *string
+	loadingDefault bool
+	defaultErr     error
+}
+
+// ColumnDefaultLoader reads the default of a column of the env, nil when it has none
+type ColumnDefaultLoader func(ctx context.Context, env, schema, table, column string) (*string, error)
+
+type columnDefaultLoadedMsg struct {
+	target *columnDetails
+	value  *string
+	err    error
+}
+
+// WithColumnDefaults tells the schema source reports column defaults, e.g. dbml files, so missing ones are shown as such
+func WithColumnDefaults() Option {
+	return func(c *runConfig) {
+		c.columnDefaults = true
+	}
+}
+
+// WithLiveColumnDefaults reads the default of a column off the database when its details open,
+// as atlas inspection does not report defaults
+func WithLiveColumnDefaults(load ColumnDefaultLoader) Option {
+	return func(c *runConfig) {
+		c.columnDefault = load
+	}
+}
+
+func (m *model) openColumnDetails() tea.Cmd {
+	col, ok := m.selectedColumn()
+	if !ok {
+		return nil
+	}
+	key := columnKey{tableKey{m.state.selectedSchema, m.state.selectedTable}, col.Name}
+	items, links := m.columnLinks(key)
+	c := &columnDetails{key: key, column: col, links: linkList{
+		items:  items,
+		empty:  styles.SubTitleStyle.Render("not part of any index or foreign key"),
+		action: "go to",
+		open:   func(item int) bool { return m.goToLink(links[item]) },
+	}}
+	m.columnDetails = c
+	m.state.overlay = types.ColumnOverlay
+	if m.config.columnDefaults || m.columnDefaultLoader == nil {
+		return nil
+	}
+	c.loadingDefault = true
+	ctx, load, env := m.ctx, m.columnDefaultLoader, m.state.env
+	return func() tea.Msg {
+		value, err := load(ctx, env, key.schemaName, key.tableName, key.columnName)
+		return columnDefaultLoadedMsg{target: c, value: value, err: err}
+	}
+}
+
+func (m *model) onColumnDefaultLoaded(msg columnDefaultLoadedMsg) {
+	c := msg.target
+	c.loadingDefault = false
+	c.liveDefault = msg.value
+	c.defaultErr = msg.err
+}
+
+// columnLinks lists the indexes and foreign keys of the column, and the foreign keys of other tables referencing it
+func (m *model) columnLinks(key columnKey) ([]linkItem, []columnLink) {
+	table := m.tablesBySchemaAndName[key.tableKey]
+	var items []linkItem
+	var links []columnLink
+	for i, idx := range table.Indexes {
+		if pos := lo.IndexOf(lo.Map(idx.Parts, func(part inspect.IndexPart, _ int) string { return part.Column }), key.columnName); pos >= 0 {
+			label := fmt.Sprintf("%s (part %d of %d%s)", idx.Name, pos+1, len(idx.Parts), lo.Ternary(idx.Parts[pos].Desc, ", desc", ""))
+			items = append(items, linkItem{section: "Indexes", label: label})
+			links = append(links, columnLink{table: key.tableKey, tab: types.IndexesTable, row: i})
+		}
+	}
+	for i, fk := range table.ForeignKeys {
+		if pos := lo.IndexOf(fk.Columns, key.columnName); pos >= 0 {
+			label := fmt.Sprintf("%s → %s(%s) (column %d of %d)", fk.Name, fk.References.Table, strings.Join(fk.References.Columns, format.InlineListSeparator), pos+1, len(fk.Columns))
+			items = append(items, linkItem{section: "Foreign keys", label: label})
+			links = append(links, columnLink{table: key.tableKey, tab: types.ForeignKeysTable, row: i})
+		}
+	}
+	for _, other := range m.schemasByName[key.schemaName].Tables {
+		for i, fk := range other.ForeignKeys {
+			if fk.References.Table == key.tableName && lo.Contains(fk.References.Columns, key.columnName) {
+				label := fmt.Sprintf("%s.%s (%s)", other.Name, fk.Name, strings.Join(fk.Columns, format.InlineListSeparator))
+				items = append(items, linkItem{section: "Referenced by", label: label})
+				links = append(links, columnLink{table: tableKey{key.schemaName, other.Name}, tab: types.ForeignKeysTable, row: i})
+			}
+		}
+	}
+	return items, links
+}
+
+// goToLink selects the table of the link and focuses the row of its tab, telling whether the table was found
+func (m *model) goToLink(link columnLink) bool {
+	if !m.selectTable(link.table.schemaName, link.table.tableName) {
+		return false
+	}
+	m.state.selectedTab = link.tab
+	m.focusDetailsRow(link.tab, link.row)
+	return true
+}
+
+func (m *model) columnDetailsView(width, height int) string {
+	c := m.columnDetails
+	table := m.tablesBySchemaAndName[c.key.tableKey]
+	col := c.column
+	or := func(value string) string { return lo.Ternary(value == "", "-", value) }
+	defaultValue := or(col.Default)
+	switch {
+	case m.config.columnDefaults:
+	case c.loadingDefault:
+		defaultValue = styles.SubTitleStyle.Render("reading from the database...")
+	case c.defaultErr != nil:
+		defaultValue = styles.ErrorStyle.Render(c.defaultErr.Error())
+	case m.columnDefaultLoader != nil:
+		defaultValue = or(lo.FromPtr(c.liveDefault))
+	case col.Default == "":
+		defaultValue = styles.SubTitleStyle.Render("not reported by atlas inspection")
+	}
+	lines := []string{
+		styles.TitleStyle.Render(fmt.Sprintf("Column %s.%s", c.key.tableName, col.Name)),
+		"",
+		fmt.Sprintf("type       %s", col.Type),
+		fmt.Sprintf("nullable   %s", format.Bool(col.Null)),
+		fmt.Sprintf("default    %s", defaultValue),
+		fmt.Sprintf("charset    %s", or(col.Charset)),
+		fmt.Sprintf("collation  %s", or(col.Collate)),
+		fmt.Sprintf("comment    %s", or(col.Comment)),
+	}
+	if table.PrimaryKey != nil {
+		if pos := lo.IndexOf(lo.Map(table.PrimaryKey.Parts, func(part inspect.IndexPart, _ int) string { return part.Column }), col.Name); pos >= 0 {
+			lines = append(lines, fmt.Sprintf("primary    part %d of %d", pos+1, len(table.PrimaryKey.Parts)))
+		}
+	}
+	return c.links.view(lines, width, height)
+}
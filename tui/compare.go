@@ -0,0 +1,366 @@
+package tui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	chart "github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/analyze"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"time"
+)
+
+const (
+	presentMarker = "●"
+	missingMarker = "○"
+	// differMarker prefixes cells that differ between the envs, charts cannot style single cells
+	differMarker = "≠ "
+)
+
+// currentSourceTitle names the inspected schema in comparisons, when it is not an env
+const currentSourceTitle = "current"
+
+// comparison is the side by side view of the current env (left) and another env, or the compare source (right)
+type comparison struct {
+	env string
+	// source is set when comparing with the compare source rather than an env
+	source bool
+	data   *inspect.Data
+	// pairedSchema is the name of the schema of data shown against the selected one under a different name
+	pairedSchema string
+	diff         inspect.Diff
+	err          error
+	cancel       context.CancelFunc
+	startedAt    time.Time
+	focused      types.FocusedComponent
+	tab          types.TableDetailsSection
+	table        string
+	tablesList   list.Model
+	charts       [2]chart.Model
+}
+
+type compareLoadedMsg struct {
+	target *comparison
+	data   *inspect.Data
+	err    error
+}
+
+// compareSourceConfig is a schema to compare the inspected one with, e.g. a schema file against the database
+type compareSourceConfig struct {
+	name string
+	load EnvLoader
+}
+
+// WithCompareSource allows comparing the inspected schema with the named one, and planning the migration to it.
+// load is given the current env, if any, e.g. for its dev database
+func WithCompareSource(name string, load EnvLoader) Option {
+	return func(c *runConfig) {
+		c.compareSource = &compareSourceConfig{name: name, load: load}
+	}
+}
+
+func (m *model) startCompare(env string) tea.Cmd {
+	if !m.loaded() || m.state.env == "" {
+		m.state.bannerErr = fmt.Errorf("wait for the current env to load before comparing")
+		return nil
+	}
+	if env == m.state.env {
+		m.state.bannerErr = fmt.Errorf("pick an env other than %s to compare with", m.state.env)
+		return nil
+	}
+	load := m.envs.load
+	return m.loadCompare(env, false, func(ctx context.Context) (*inspect.Data, error) {
+		return load(ctx, env, nil)
+	})
+}
+
+func (m *model) startSourceCompare() tea.Cmd {
+	if !m.loaded() {
+		m.state.bannerErr = fmt.Errorf("wait for the schema to load before comparing")
+		return nil
+	}
+	load, env := m.compareSource.load, m.state.env
+	return m.loadCompare(m.compareSource.name, true, func(ctx context.Context) (*inspect.Data, error) {
+		return load(ctx, env, nil)
+	})
+}
+
+func (m *model) loadCompare(name string, source bool, load func(ctx context.Context) (*inspect.Data, error)) tea.Cmd {
+	ctx, cancel := context.WithCancel(m.ctx)
+	c := &comparison{
+		env:       name,
+		source:    source,
+		cancel:    cancel,
+		startedAt: time.Now(),
+		tab:       lo.Ternary(m.state.selectedTab == types.DataTable, types.ColumnsTable, m.state.selectedTab),
+		table:     m.state.selectedTable,
+	}
+	m.compare = c
+	m.state.overlay = types.CompareOverlay
+	return func() tea.Msg {
+		defer cancel()
+		data, err := load(ctx)
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("cancelled after %s", formatElapsed(time.Since(c.startedAt)))
+		}
+		if err == nil && len(data.Schemas) == 0 {
+			err = fmt.Errorf("no schema found")
+		}
+		return compareLoadedMsg{target: c, data: data, err: err}
+	}
+}
+
+func (c *comparison) title() string {
+	return lo.Ternary(c.source, c.env, "env "+c.env)
+}
+
+// currentTitle names the inspected schema, by its env when there is one
+func (m *model) currentTitle() string {
+	return cmp.Or(m.state.env, currentSourceTitle)
+}
+
+func (m *model) onCompareLoaded(msg compareLoadedMsg) {
+	c := m.compare
+	// the comparison may have been closed, or replaced, while loading
+	if c == nil || c != msg.target {
+		return
+	}
+	c.cancel = nil
+	if msg.err != nil {
+		c.err = msg.err
+		return
+	}
+	c.data, c.pairedSchema = pairSchema(msg.data, m.state.selectedSchema, lo.Keys(m.schemasByName))
+	c.diff = inspect.Compare(*m.data, *c.data)
+	m.onCompareSchemaLoaded()
+}
+
+// pairSchema names the only schema of the compared data after the selected schema, when the names differ and the
+// current side has no schema by its name, e.g. a sqlite "main" against a postgres "public".
+// It returns the data to compare along with the original name of the renamed schema.
+func pairSchema(data *inspect.Data, selected string, current []string) (*inspect.Data, string) {
+	if len(data.Schemas) != 1 || data.Schemas[0].Name == selected || lo.Contains(current, data.Schemas[0].Name) {
+		return data, ""
+	}
+	paired := *data
+	paired.Schemas = []inspect.Schema{data.Schemas[0]}
+	paired.Schemas[0].Name = selected
+	return &paired, data.Schemas[0].Name
+}
+
+func (m *model) closeCompare() {
+	if m.compare != nil && m.compare.cancel != nil {
+		m.compare.cancel()
+	}
+	m.compare = nil
+	m.state.overlay = types.NoOverlay
+}
+
+func (m *model) onCompareSchemaLoaded() {
+	c := m.compare
+	left := m.schemasByName[m.state.selectedSchema]
+	right, _ := lo.Find(c.data.Schemas, func(schema inspect.Schema) bool {
+		return schema.Name == m.state.selectedSchema
+	})
+	names := unionNames(left.Tables, right.Tables, func(table inspect.Table) string {
+		return table.Name
+	})
+	leftNames := lo.SliceToMap(left.Tables, func(table inspect.Table) (string, bool) { return table.Name, true })
+	rightNames := lo.SliceToMap(right.Tables, func(table inspect.Table) (string, bool) { return table.Name, true })
+	c.tablesList = newTablesList(lo.Map(names, func(name string, _ int) types.TablesListItem {
+		return types.TablesListItem{
+			Name: name,
+			Marker: fmt.Sprintf("%s%s%s ",
+				lo.Ternary(leftNames[name], presentMarker, missingMarker),
+				lo.Ternary(rightNames[name], presentMarker, missingMarker),
+				lo.Ternary(c.diff.Table(m.state.selectedSchema, name).Change == inspect.Modified, "≠", " "),
+			),
+		}
+	}))
+	c.tablesList.SetStatusBarItemName("table", "tables")
+	if idx := lo.IndexOf(names, c.table); idx >= 0 {
+		c.tablesList.Select(idx)
+	} else if len(names) > 0 {
+		c.table = names[0]
+	}
+	m.onCompareTableSelected()
+}
+
+func (m *model) onCompareTableSelected() {
+	c := m.compare
+	right, _ := lo.Find(c.data.Schemas, func(schema inspect.Schema) bool {
+		return schema.Name == m.state.selectedSchema
+	})
+	leftTable := m.tablesBySchemaAndName[tableKey{m.state.selectedSchema, c.table}]
+	rightTable, _ := lo.Find(right.Tables, func(table inspect.Table) bool {
+		return table.Name == c.table
+	})
+
+	var cols []chart.Column
+	var leftRows, rightRows []chart.Row
+	switch c.tab {
+	case types.ColumnsTable:
+		cols = colsChartColumns
+		leftCoverage, rightCoverage := analyze.IndexCoverage(leftTable), analyze.IndexCoverage(rightTable)
+		leftRows, rightRows = alignRows(leftTable.Columns, rightTable.Columns, len(cols),
+			func(col inspect.Column) string { return col.Name },
+			func(col inspect.Column) chart.Row { return columnRow(leftTable, col, leftCoverage[col.Name]) },
+			func(col inspect.Column) chart.Row { return columnRow(rightTable, col, rightCoverage[col.Name]) },
+		)
+	case types.IndexesTable:
+		cols = idxChartColumns
+		leftRows, rightRows = alignRows(leftTable.Indexes, rightTable.Indexes, len(cols),
+			func(idx inspect.Index) string { return idx.Name },
+			indexRow,
+			indexRow,
+		)
+	case types.ForeignKeysTable:
+		cols = fksChartColumns
+		leftRows, rightRows = alignRows(leftTable.ForeignKeys, rightTable.ForeignKeys, len(cols),
+			func(fk inspect.ForeignKey) string { return fk.Name },
+			foreignKeyRow,
+			foreignKeyRow,
+		)
+	}
+	c.charts = [2]chart.Model{newChart(cols, leftRows), newChart(cols, rightRows)}
+}
+
+// alignRows renders the rows of both sides by name, leaving an empty row where an item is missing on one side
+func alignRows[T any](left, right []T, width int, name func(T) string, leftRow, rightRow func(T) chart.Row) (leftRows, rightRows []chart.Row) {
+	leftByName := lo.KeyBy(left, name)
+	rightByName := lo.KeyBy(right, name)
+	for _, itemName := range unionNames(left, right, name) {
+		l, inLeft := leftByName[itemName]
+		r, inRight := rightByName[itemName]
+		lrow := lo.Ternary(inLeft, leftRow(l), make(chart.Row, width))
+		rrow := lo.Ternary(inRight, rightRow(r), make(chart.Row, width))
+		if inLeft && inRight {
+			for i := range lrow {
+				if lrow[i] != rrow[i] {
+					lrow[i] = differMarker + lrow[i]
+					rrow[i] = differMarker + rrow[i]
+				}
+			}
+		}
+		leftRows = append(leftRows, lrow)
+		rightRows = append(rightRows, rrow)
+	}
+	return
+}
+
+// unionNames lists the names of the left items in order, followed by those found only on the right
+func unionNames[T any](left, right []T, name func(T) string) []string {
+	names := lo.Map(left, func(item T, _ int) string { return name(item) })
+	for _, item := range right {
+		if !lo.Contains(names, name(item)) {
+			names = append(names, name(item))
+		}
+	}
+	return names
+}
+
+func (m *model) updateCompare(msg tea.KeyMsg) tea.Cmd {
+	c := m.compare
+	switch {
+	case c.cancel != nil && key.Matches(msg, keymap.Cancel):
+		c.cancel()
+	case key.Matches(msg, keymap.Back):
+		m.closeCompare()
+	case key.Matches(msg, keymap.Quit):
+		m.state.quitting = true
+		return tea.Quit
+	case c.data == nil:
+		// nothing to navigate yet
+	case key.Matches(msg, keymap.Migrate):
+		return m.startPlan()
+	case key.Matches(msg, keymap.Tab):
+		c.focused = lo.Ternary(c.focused == types.TablesListFocused, types.DetailsContentsFocused, types.TablesListFocused)
+	case key.Matches(msg, keymap.Left):
+		c.tab = (c.tab + 2) % 3
+		m.onCompareTableSelected()
+	case key.Matches(msg, keymap.Right):
+		c.tab = (c.tab + 1) % 3
+		m.onCompareTableSelected()
+	case key.Matches(msg, keymap.Up), key.Matches(msg, keymap.Down):
+		var cmd tea.Cmd
+		if c.focused == types.TablesListFocused {
+			c.tablesList, cmd = c.tablesList.Update(msg)
+			if item := c.tablesList.SelectedItem(); item != nil && item.FilterValue() != c.table {
+				c.table = item.FilterValue()
+				m.onCompareTableSelected()
+			}
+			return cmd
+		}
+		// both sides hold aligned rows, so they scroll together
+		c.charts[0], cmd = c.charts[0].Update(msg)
+		c.charts[1].SetCursor(c.charts[0].Cursor())
+		return cmd
+	}
+	return nil
+}
+
+func (m *model) compareView(width, height int) string {
+	c := m.compare
+	if c.data == nil {
+		var lines []string
+		if c.err != nil {
+			lines = []string{styles.ErrorStyle.Render(fmt.Sprintf("Failed to inspect %s", c.title())), "", styles.ErrorStyle.Render(c.err.Error())}
+		} else {
+			lines = []string{
+				fmt.Sprintf("%s Inspecting %s... %s", m.vms.globe.View(), c.title(), formatElapsed(time.Since(c.startedAt))),
+				styles.SubTitleStyle.Render(fmt.Sprintf("press %s to cancel", keymap.Cancel.Help().Key)),
+			}
+		}
+		return withBorder(centeredBox(width, height, lines...), true)
+	}
+
+	// the given sizes exclude a single border around the whole view
+	totalWidth, totalHeight := width+2, height+2
+	listWidth := totalWidth / 4
+	c.tablesList.SetSize(listWidth-2, totalHeight-2)
+	tablesList := withBorder(lipgloss.NewStyle().Width(listWidth-2).Render(c.tablesList.View()), c.focused == types.TablesListFocused)
+
+	detailsWidth := totalWidth - listWidth
+	tabs := tabsView(types.SchemaSections, c.tab, detailsWidth-2, false, "")
+	legend := styles.SubTitleStyle.Render(fmt.Sprintf("%s%s present in %s/%s · %s missing · %sdiffers · %s plan migration",
+		presentMarker, presentMarker, m.currentTitle(), c.env, missingMarker, differMarker, keymap.Migrate.Help().Key))
+
+	chartsHeight := totalHeight - lipgloss.Height(tabs) - 1
+	charts := make([]string, 0, 2)
+	for i, env := range []string{m.currentTitle(), c.env + lo.Ternary(c.pairedSchema != "", " · "+c.pairedSchema, "")} {
+		chartWidth := detailsWidth/2 - 2
+		if i == 1 {
+			chartWidth = detailsWidth - detailsWidth/2 - 2
+		}
+		header := lipgloss.NewStyle().Width(chartWidth).Align(lipgloss.Center).Render(styles.BreadcrumbsTitleStyle.Render(env))
+		c.charts[i].SetWidth(chartWidth)
+		c.charts[i].SetHeight(chartsHeight - 4)
+		var body string
+		if len(c.charts[i].Rows()) == 0 {
+			body = styles.NoDataStyle.Copy().Width(chartWidth).Height(chartsHeight - 4).Render(fmt.Sprintf("No %s", c.tab.Title()))
+		} else {
+			body = c.charts[i].View()
+		}
+		charts = append(charts, withBorder(lipgloss.JoinVertical(lipgloss.Left, header, body), c.focused == types.DetailsContentsFocused))
+	}
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		tablesList,
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			tabs,
+			lipgloss.NewStyle().Width(detailsWidth).Align(lipgloss.Center).Render(legend),
+			lipgloss.JoinHorizontal(lipgloss.Top, charts...),
+		),
+	)
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/reallyliri/atlastui/analyze"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/format"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"strings"
+)
+
+// indexCoverage lists the lookups the indexes of the selected schema do not serve
+type indexCoverage struct {
+	schemaName string
+	missing    linkList
+}
+
+func (m *model) openCoverage() {
+	schema := m.schemasByName[m.state.selectedSchema]
+	missing := lo.FlatMap(schema.Tables, func(t inspect.Table, _ int) []analyze.MissingIndex {
+		return analyze.MissingIndexes(t)
+	})
+	m.coverage = &indexCoverage{
+		schemaName: schema.Name,
+		missing: linkList{
+			items: lo.Map(missing, func(missing analyze.MissingIndex, _ int) linkItem {
+				label := fmt.Sprintf("%s · %s · indexed %s", strings.Join(missing.Columns, format.InlineListSeparator), missing.Reason, missing.Coverage)
+				return linkItem{section: missing.Table, label: label}
+			}),
+			empty:  "every foreign key and commonly filtered column leads an index",
+			action: "go to column",
+			open: func(item int) bool {
+				key := tableKey{schema.Name, missing[item].Table}
+				columns := lo.Map(m.tablesBySchemaAndName[key].Columns, func(col inspect.Column, _ int) string { return col.Name })
+				return m.goToLink(columnLink{table: key, tab: types.ColumnsTable, row: lo.IndexOf(columns, missing[item].Columns[0])})
+			},
+		},
+	}
+	m.state.overlay = types.CoverageOverlay
+}
+
+func (m *model) coverageView(width, height int) string {
+	c := m.coverage
+	return c.missing.view([]string{
+		styles.TitleStyle.Render(fmt.Sprintf("Index coverage of %s", c.schemaName)),
+		styles.SubTitleStyle.Render("foreign keys and commonly filtered columns no index leads with"),
+	}, width, height)
+}
@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	chart "github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reallyliri/atlastui/db"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	nullMarker = "∅"
+	// maxCellWidth truncates long values, e.g. json documents, so other columns stay in sight
+	maxCellWidth = 32
+)
+
+// DataLoader fetches a page of the rows of a table of the env, ordered by the given columns
+type DataLoader func(ctx context.Context, env, schema, table string, orderBy []string, offset, limit int) (*db.Page, error)
+
+type dataPreviewConfig struct {
+	load     DataLoader
+	pageSize int
+}
+
+// tableData is the displayed page of rows of the selected table
+type tableData struct {
+	key       tableKey
+	offset    int
+	page      *db.Page
+	loading   bool
+	err       error
+	startedAt time.Time
+	chart     chart.Model
+}
+
+type dataPageLoadedMsg struct {
+	target *tableData
+	page   *db.Page
+	err    error
+}
+
+// WithDataPreview adds a read-only Data tab, showing the rows of the selected table page by page
+func WithDataPreview(load DataLoader, pageSize int) Option {
+	return func(c *runConfig) {
+		c.dataPreview = &dataPreviewConfig{load: load, pageSize: pageSize}
+	}
+}
+
+// sections are the tabs of the table details
+func (m *model) sections() []types.TableDetailsSection {
+	if m.dataPreview == nil {
+		return types.SchemaSections
+	}
+	return append(slices.Clone(types.SchemaSections), types.DataTable)
+}
+
+func (m *model) startDataPage(offset int) tea.Cmd {
+	if m.dataPreview == nil {
+		return nil
+	}
+	key := tableKey{m.state.selectedSchema, m.state.selectedTable}
+	d := &tableData{key: key, offset: offset, loading: true, startedAt: time.Now()}
+	if prev := m.tableData; prev != nil && prev.key == key {
+		// keep the current page in sight while the next one loads
+		d.page, d.chart = prev.page, prev.chart
+	}
+	m.tableData = d
+	table := m.tablesBySchemaAndName[key]
+	var orderBy []string
+	if table.PrimaryKey != nil {
+		orderBy = lo.FilterMap(table.PrimaryKey.Parts, func(part inspect.IndexPart, _ int) (string, bool) {
+			return part.Column, part.Column != ""
+		})
+	}
+	load, limit, ctx, env := m.dataPreview.load, m.dataPreview.pageSize, m.ctx, m.state.env
+	return func() tea.Msg {
+		page, err := load(ctx, env, key.schemaName, key.tableName, orderBy, offset, limit)
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("cancelled after %s", formatElapsed(time.Since(d.startedAt)))
+		}
+		return dataPageLoadedMsg{target: d, page: page, err: err}
+	}
+}
+
+// refreshDataPage re-fetches the displayed page, e.g. after a reload
+func (m *model) refreshDataPage() tea.Cmd {
+	if m.state.selectedTab != types.DataTable {
+		return nil
+	}
+	offset := 0
+	if d := m.tableData; d != nil && d.key == (tableKey{m.state.selectedSchema, m.state.selectedTable}) {
+		offset = d.offset
+	}
+	return m.startDataPage(offset)
+}
+
+func (m *model) onDataPageLoaded(msg dataPageLoadedMsg) {
+	d := m.tableData
+	if d == nil || d != msg.target {
+		return
+	}
+	d.loading = false
+	d.err = msg.err
+	if msg.err != nil {
+		return
+	}
+	d.page = msg.page
+	// flex widths account for cell padding only when they sum to twice the columns count
+	d.chart = newChart(
+		lo.Map(d.page.Columns, func(column string, _ int) chart.Column { return chart.Column{Title: column, Width: 2} }),
+		lo.Map(d.page.Rows, func(row []*string, _ int) chart.Row {
+			return lo.Map(row, func(value *string, _ int) string { return dataCell(value) })
+		}),
+	)
+}
+
+func dataCell(value *string) string {
+	if value == nil {
+		return nullMarker
+	}
+	cell := strings.Join(strings.Fields(*value), " ")
+	if runes := []rune(cell); len(runes) > maxCellWidth {
+		cell = string(runes[:maxCellWidth-1]) + "…"
+	}
+	return cell
+}
+
+func (m *model) nextDataPage(forward bool) tea.Cmd {
+	d := m.tableData
+	switch {
+	case d == nil || d.loading || d.page == nil:
+		return nil
+	case forward && d.page.HasMore:
+		return m.startDataPage(d.offset + m.dataPreview.pageSize)
+	case !forward && d.offset > 0:
+		return m.startDataPage(lo.Max([]int{0, d.offset - m.dataPreview.pageSize}))
+	default:
+		return nil
+	}
+}
+
+// dataSummary describes the displayed page, for the table header
+func (m *model) dataSummary() string {
+	d := m.tableData
+	switch {
+	case d == nil:
+		return ""
+	case d.loading:
+		return fmt.Sprintf("%s fetching rows... %s", m.vms.globe.View(), formatElapsed(time.Since(d.startedAt)))
+	case d.err != nil || d.page == nil:
+		return "read-only"
+	case len(d.page.Rows) == 0:
+		return lo.Ternary(d.offset == 0, "read-only · no rows", "read-only · no more rows")
+	}
+	summary := fmt.Sprintf("read-only · rows %d-%d", d.offset+1, d.offset+len(d.page.Rows))
+	if d.offset > 0 {
+		summary += fmt.Sprintf(" · %s previous", keymap.PrevPage.Help().Key)
+	}
+	if d.page.HasMore {
+		summary += fmt.Sprintf(" · %s next", keymap.NextPage.Help().Key)
+	}
+	return summary
+}
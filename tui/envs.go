@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/project"
+	"github.com/reallyliri/atlastui/snapshot"
+	"github.com/reallyliri/atlastui/tui/format"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"strings"
+)
+
+type envsConfig struct {
+	project *project.Project
+	load    EnvLoader
+}
+
+type envItem struct {
+	project.Env
+	current bool
+}
+
+var _ list.DefaultItem = envItem{}
+
+func (e envItem) FilterValue() string {
+	return e.Name
+}
+
+func (e envItem) Title() string {
+	return lo.Ternary(e.current, e.Name+" (current)", e.Name)
+}
+
+func (e envItem) Description() string {
+	parts := make([]string, 0, 3)
+	// unresolved values are shown as written in the project file
+	unresolved := func(attr string) string { return lo.Ternary(e.Resolved(attr), "", " (unresolved)") }
+	if e.URL != "" {
+		parts = append(parts, "url: "+snapshot.RedactURL(e.URL)+unresolved("url"))
+	}
+	if e.DevURL != "" {
+		parts = append(parts, "dev: "+snapshot.RedactURL(e.DevURL)+unresolved("dev"))
+	}
+	if len(e.Schemas) > 0 {
+		parts = append(parts, "schemas: "+strings.Join(e.Schemas, format.InlineListSeparator)+unresolved("schemas"))
+	}
+	return strings.Join(parts, format.TabsSeparator)
+}
+
+// WithEnvs allows picking the env of the project to inspect, which load inspects in place of the loader of Run.
+// When current is empty, the env is picked before the first load. load is also used to compare the current env with another.
+func WithEnvs(proj *project.Project, current string, load EnvLoader) Option {
+	return func(c *runConfig) {
+		c.envs = &envsConfig{project: proj, load: load}
+		c.env = current
+	}
+}
+
+func (m *model) openEnvs() {
+	if m.envs == nil {
+		m.state.bannerErr = fmt.Errorf("no atlas project environments to switch to")
+		return
+	}
+	delegate := list.NewDefaultDelegate()
+	delegate.SetSpacing(0)
+	lst := list.New(
+		lo.Map(m.envs.project.Envs, func(env project.Env, _ int) list.Item {
+			return envItem{Env: env, current: env.Name == m.state.env}
+		}),
+		delegate,
+		0,
+		0,
+	)
+	lst.SetFilteringEnabled(false)
+	lst.SetShowHelp(false)
+	lst.Title = fmt.Sprintf("Environments of %s", m.envs.project.Path)
+	lst.Styles.Title = styles.TitleStyle
+	lst.SetStatusBarItemName("env", "envs")
+	if idx := lo.IndexOf(lo.Map(m.envs.project.Envs, func(env project.Env, _ int) string {
+		return env.Name
+	}), m.state.env); idx >= 0 {
+		lst.Select(idx)
+	}
+	m.vms.envsList = lst
+	m.state.overlay = types.EnvsOverlay
+}
+
+func (m *model) updateEnvs(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keymap.Back):
+		// there is nothing to go back to before the first env is picked
+		if m.state.env != "" || m.loaded() {
+			m.state.overlay = types.NoOverlay
+		}
+	case key.Matches(msg, keymap.Compare):
+		item, ok := m.vms.envsList.SelectedItem().(envItem)
+		if !ok {
+			return nil
+		}
+		return m.startCompare(item.Name)
+	case key.Matches(msg, keymap.Select):
+		item, ok := m.vms.envsList.SelectedItem().(envItem)
+		if !ok {
+			return nil
+		}
+		if m.state.loading {
+			m.state.bannerErr = fmt.Errorf("wait for loading to complete, or cancel it with %s", keymap.Cancel.Help().Key)
+			return nil
+		}
+		m.state.overlay = types.NoOverlay
+		return m.switchEnv(item.Name)
+	case key.Matches(msg, keymap.Quit):
+		m.state.quitting = true
+		return tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.vms.envsList, cmd = m.vms.envsList.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// switchEnv inspects the given env, the current env is kept until the inspection succeeds
+func (m *model) switchEnv(env string) tea.Cmd {
+	m.state.pendingEnv = env
+	return m.startLoad()
+}
+
+// onEnvLoaded settles a pending env switch, reverting to the current env if loading failed
+func (m *model) onEnvLoaded(ok bool) (switched bool) {
+	if m.state.pendingEnv == "" {
+		return false
+	}
+	if ok {
+		switched = m.state.pendingEnv != m.state.env
+		m.state.env = m.state.pendingEnv
+	}
+	m.state.pendingEnv = ""
+	return switched
+}
+
+func (m *model) envsView(width, height int) string {
+	help := styles.SubTitleStyle.Copy().PaddingLeft(2).Render(fmt.Sprintf(
+		"%s inspect · %s compare with current · %s close · %s quit",
+		keymap.Select.Help().Key, keymap.Compare.Help().Key, keymap.Back.Help().Key, keymap.Quit.Help().Key,
+	))
+	m.vms.envsList.SetSize(width, height-1)
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, m.vms.envsList.View(), help),
+	), true)
+}
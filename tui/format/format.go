@@ -1,9 +1,13 @@
 package format
 
 import (
+	"fmt"
+	"github.com/reallyliri/atlastui/analyze"
 	"github.com/reallyliri/atlastui/inspect"
 	"github.com/samber/lo"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -33,3 +37,66 @@ func ColumnName(table inspect.Table, col inspect.Column) string {
 	}
 	return sb.String()
 }
+
+// Coverage marks how a column is covered by indexes, leaving uncovered columns blank
+func Coverage(c analyze.Coverage) string {
+	if c == analyze.NotCovered {
+		return "-"
+	}
+	return c.String()
+}
+
+func ChangeMarker(change inspect.Change) string {
+	switch change {
+	case inspect.Added:
+		return "+ "
+	case inspect.Removed:
+		return "- "
+	case inspect.Modified:
+		return "~ "
+	default:
+		return "  "
+	}
+}
+
+// Count abbreviates large counts, e.g. 1.2M, negative counts are unknown
+func Count(n int64) string {
+	switch {
+	case n < 0:
+		return "-"
+	case n < 1_000:
+		return strconv.FormatInt(n, 10)
+	case n < 1_000_000:
+		return fmt.Sprintf("%.1fK", float64(n)/1_000)
+	case n < 1_000_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	default:
+		return fmt.Sprintf("%.1fB", float64(n)/1_000_000_000)
+	}
+}
+
+// Bytes formats sizes in binary units, e.g. 1.5 MiB, negative sizes are unknown
+func Bytes(n int64) string {
+	if n < 0 {
+		return "-"
+	}
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	size := float64(n)
+	unit := 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", n, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", size, units[unit])
+}
+
+// Date formats the date part of times, zero times are unknown
+func Date(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateOnly)
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/reallyliri/atlastui/analyze"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"strings"
+)
+
+// schemaGraph is the foreign keys graph of the selected schema
+type schemaGraph struct {
+	graph  analyze.Graph
+	tables linkList
+}
+
+func (m *model) openGraph() {
+	g := analyze.SchemaGraph(m.schemasByName[m.state.selectedSchema])
+	items, tables := graphItems(g)
+	m.graph = &schemaGraph{graph: g, tables: linkList{
+		items:  items,
+		empty:  "no tables",
+		action: "go to table",
+		open:   func(item int) bool { return m.selectTable(g.Schema, tables[item]) },
+	}}
+	m.state.overlay = types.GraphOverlay
+}
+
+// graphItems are the lines of the graph panel, along with the table each of them leads to
+func graphItems(g analyze.Graph) ([]linkItem, []string) {
+	inCycle := lo.SliceToMap(lo.Flatten(g.Cycles), func(table string) (string, bool) { return table, true })
+	var items []linkItem
+	var tables []string
+	add := func(section, label, table string) {
+		items = append(items, linkItem{section: section, label: label})
+		tables = append(tables, table)
+	}
+	for i, table := range g.LoadOrder {
+		add("Load order (reversed to truncate)", fmt.Sprintf("%3d. %s%s", i+1, table, lo.Ternary(inCycle[table], " ↻", "")), table)
+	}
+	for _, cycle := range g.Cycles {
+		add("Cycles", strings.Join(cycle, " ⇄ "), cycle[0])
+	}
+	for _, table := range g.Isolated {
+		add("Isolated tables", table, table)
+	}
+	for _, hub := range g.Hubs {
+		add("Hub tables", fmt.Sprintf("%s (%d %s)", hub.Table, hub.References, lo.Ternary(hub.References == 1, "reference", "references")), hub.Table)
+	}
+	return items, tables
+}
+
+func (m *model) graphView(width, height int) string {
+	g := m.graph
+	cycles := len(g.graph.Cycles)
+	summary := fmt.Sprintf("%d tables · %d %s · %d isolated", len(g.graph.LoadOrder), cycles, lo.Ternary(cycles == 1, "cycle", "cycles"), len(g.graph.Isolated))
+	return g.tables.view([]string{
+		styles.TitleStyle.Render(fmt.Sprintf("Foreign keys graph of %s", g.graph.Schema)),
+		styles.SubTitleStyle.Render(summary),
+	}, width, height)
+}
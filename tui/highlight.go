@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"github.com/reallyliri/atlastui/tui/styles"
+	"regexp"
+	"strings"
+)
+
+var (
+	sqlTokenPattern = regexp.MustCompile("(--[^\\n]*|/\\*[\\s\\S]*?\\*/)|('(?:[^']|'')*'?)|(\"[^\"]*\"|`[^`]*`)|(\\b\\d+(?:\\.\\d+)?\\b)|(\\b[A-Za-z_]+\\b)")
+	sqlKeywords     = toSet(
+		"ADD", "ALL", "ALTER", "AND", "AS", "ASC", "AUTO_INCREMENT", "BEGIN", "BETWEEN", "BY", "CASCADE", "CASE", "CHANGE",
+		"CHECK", "COLUMN", "COMMENT", "COMMIT", "CONCURRENTLY", "CONSTRAINT", "CREATE", "DATABASE", "DEFAULT", "DELETE",
+		"DESC", "DISTINCT", "DROP", "ELSE", "END", "EXISTS", "FOREIGN", "FROM", "FULLTEXT", "GROUP", "HAVING", "IF", "IN",
+		"INDEX", "INNER", "INSERT", "INTO", "IS", "JOIN", "KEY", "LEFT", "LIKE", "LIMIT", "MODIFY", "NOT", "NULL", "OFFSET",
+		"ON", "ONLY", "OR", "ORDER", "OUTER", "PRIMARY", "REFERENCES", "RENAME", "RESTRICT", "RIGHT", "ROLLBACK", "SCHEMA",
+		"SELECT", "SET", "SPATIAL", "TABLE", "THEN", "TO", "TRANSACTION", "TRUNCATE", "TYPE", "UNION", "UNIQUE", "UPDATE",
+		"USING", "VALUES", "VIEW", "WHEN", "WHERE", "WITH",
+	)
+)
+
+// highlightSQL colors sql keywords, literals and comments for display
+func highlightSQL(sql string) string {
+	return sqlTokenPattern.ReplaceAllStringFunc(sql, func(tok string) string {
+		switch {
+		case strings.HasPrefix(tok, "--"), strings.HasPrefix(tok, "/*"):
+			return styles.SQLCommentStyle.Render(tok)
+		case strings.HasPrefix(tok, "'"):
+			return styles.SQLLiteralStyle.Render(tok)
+		case strings.HasPrefix(tok, "\""), strings.HasPrefix(tok, "`"):
+			return tok
+		case tok[0] >= '0' && tok[0] <= '9':
+			return styles.SQLLiteralStyle.Render(tok)
+		case sqlKeywords[strings.ToUpper(tok)]:
+			return styles.SQLKeywordStyle.Render(tok)
+		default:
+			return tok
+		}
+	})
+}
+
+func toSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
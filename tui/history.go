@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/snapshot"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"time"
+)
+
+const snapshotTimeLayout = "2006-01-02 15:04:05"
+
+type historyConfig struct {
+	store  *snapshot.Store
+	source SourceNamer
+	// save is unset when browsing the history of an opened snapshot, which is not saved again
+	save bool
+}
+
+type snapshotItem snapshot.Entry
+
+var _ list.DefaultItem = snapshotItem{}
+
+func (s snapshotItem) FilterValue() string {
+	return s.ID
+}
+
+func (s snapshotItem) Title() string {
+	return s.TakenAt.Local().Format(snapshotTimeLayout)
+}
+
+func (s snapshotItem) Description() string {
+	return fmt.Sprintf("%s ago · %s", formatAge(time.Since(s.TakenAt)), s.ID)
+}
+
+// WithHistory saves a snapshot of each successful load from the source of its env, and enables browsing them
+func WithHistory(store *snapshot.Store, source SourceNamer) Option {
+	return func(c *runConfig) {
+		c.history = &historyConfig{store: store, source: source, save: true}
+	}
+}
+
+// WithSnapshotHistory enables browsing the snapshots of the source of an opened snapshot, without saving any
+func WithSnapshotHistory(store *snapshot.Store, source string) Option {
+	return func(c *runConfig) {
+		c.history = &historyConfig{store: store, source: func(string) string { return source }}
+	}
+}
+
+func (m *model) openHistory() {
+	if m.history == nil || m.history.source(m.state.env) == "" {
+		m.state.bannerErr = fmt.Errorf("history is only kept for database inspections")
+		return
+	}
+	entries, err := m.history.store.List(m.history.source(m.state.env))
+	if err != nil {
+		m.state.bannerErr = err
+		return
+	}
+	delegate := list.NewDefaultDelegate()
+	delegate.SetSpacing(0)
+	lst := list.New(
+		lo.Map(entries, func(entry snapshot.Entry, _ int) list.Item {
+			return snapshotItem(entry)
+		}),
+		delegate,
+		0,
+		0,
+	)
+	lst.SetFilteringEnabled(false)
+	lst.SetShowHelp(false)
+	lst.Title = fmt.Sprintf("Snapshots of %s", m.history.source(m.state.env))
+	lst.Styles.Title = styles.TitleStyle
+	lst.SetStatusBarItemName("snapshot", "snapshots")
+	m.vms.historyList = lst
+	m.state.overlay = types.HistoryOverlay
+}
+
+func (m *model) updateHistory(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keymap.Back):
+		m.state.overlay = types.NoOverlay
+	case key.Matches(msg, keymap.Select), key.Matches(msg, keymap.Diff):
+		item, ok := m.vms.historyList.SelectedItem().(snapshotItem)
+		if !ok {
+			return nil
+		}
+		snap, err := m.history.store.Load(item.Path)
+		if err != nil {
+			m.state.bannerErr = err
+			return nil
+		}
+		m.state.overlay = types.NoOverlay
+		if key.Matches(msg, keymap.Diff) {
+			m.compareWith(snap.Data, fmt.Sprintf("compared with snapshot of %s", item.Title()))
+		} else {
+			m.openSnapshot(*snap, item.Title())
+		}
+	default:
+		var cmd tea.Cmd
+		m.vms.historyList, cmd = m.vms.historyList.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// openSnapshot displays an old snapshot instead of the live data, until the next reload
+func (m *model) openSnapshot(snap snapshot.Snapshot, title string) {
+	m.diff = inspect.Diff{}
+	m.state.diffTitle = ""
+	m.state.viewing = fmt.Sprintf("viewing snapshot of %s", title)
+	m.state.loadErr = nil
+	m.setData(snap.Data)
+}
+
+// compareWith highlights the changes from the given data to the currently displayed data
+func (m *model) compareWith(base inspect.Data, title string) {
+	m.diff = inspect.Compare(base, *m.data)
+	m.state.diffTitle = title
+	m.state.loadErr = nil
+	m.setData(*m.data)
+}
+
+// saveSnapshot saves the data loaded from the env, it is called while loading so it reads no model state
+func (m *model) saveSnapshot(env string, data inspect.Data) error {
+	if m.history == nil || !m.history.save {
+		return nil
+	}
+	source := m.history.source(env)
+	if source == "" {
+		return nil
+	}
+	_, err := m.history.store.Save(source, data)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+func (m *model) historyView(width, height int) string {
+	help := styles.SubTitleStyle.Copy().PaddingLeft(2).Render(fmt.Sprintf(
+		"%s open · %s diff with current · %s close",
+		keymap.Select.Help().Key, keymap.Diff.Help().Key, keymap.Back.Help().Key,
+	))
+	if len(m.vms.historyList.Items()) == 0 {
+		return withBorder(styles.NoDataStyle.Copy().Width(width).Height(height).Render("No snapshots yet\n\n"+help), true)
+	}
+	m.vms.historyList.SetSize(width, height-1)
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, m.vms.historyList.View(), help),
+	), true)
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "moments"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
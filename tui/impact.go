@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/reallyliri/atlastui/analyze"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+)
+
+// columnImpact is what breaks when the selected column is renamed or dropped
+type columnImpact struct {
+	key   columnKey
+	nodes linkList
+}
+
+func (m *model) openImpact() {
+	col, ok := m.selectedColumn()
+	if m.state.selectedTab != types.ColumnsTable || !ok {
+		m.state.bannerErr = fmt.Errorf("select a column to analyze in the %s tab", types.ColumnsTable.Title())
+		return
+	}
+	key := columnKey{tableKey{m.state.selectedSchema, m.state.selectedTable}, col.Name}
+	impacts := analyze.ColumnImpact(m.schemasByName[key.schemaName], key.tableName, key.columnName)
+	items, links := m.impactNodes(key.schemaName, impacts, "")
+	m.impact = &columnImpact{key: key, nodes: linkList{
+		items:  items,
+		empty:  "nothing depends on this column",
+		action: "go to",
+		open:   func(item int) bool { return m.goToLink(links[item]) },
+	}}
+	m.state.overlay = types.ImpactOverlay
+}
+
+// impactNodes flattens the impact tree into lines, drawing the branches leading to each line, along with where each line leads
+func (m *model) impactNodes(schemaName string, impacts []analyze.Impact, prefix string) ([]linkItem, []columnLink) {
+	var items []linkItem
+	var links []columnLink
+	for i, impact := range impacts {
+		last := i == len(impacts)-1
+		items = append(items, linkItem{prefix: prefix + lo.Ternary(last, "└─ ", "├─ "), label: impactLabel(impact)})
+		links = append(links, m.impactLink(schemaName, impact))
+		childItems, childLinks := m.impactNodes(schemaName, impact.Children, prefix+lo.Ternary(last, "   ", "│  "))
+		items, links = append(items, childItems...), append(links, childLinks...)
+	}
+	return items, links
+}
+
+func impactLabel(impact analyze.Impact) string {
+	switch impact.Kind {
+	case analyze.IndexImpact:
+		return fmt.Sprintf("index %s (part %d of %d)", impact.Name, impact.Part, impact.Parts)
+	case analyze.ForeignKeyImpact:
+		return fmt.Sprintf("foreign key %s (column %d of %d)", impact.Name, impact.Part, impact.Parts)
+	default:
+		return fmt.Sprintf("%s.%s via %s%s", impact.Table, impact.Column, impact.Name, lo.Ternary(impact.Cycle, " ↻ cycle", ""))
+	}
+}
+
+// impactLink leads to the row of the index or foreign key, the primary key leads to the row of the column
+func (m *model) impactLink(schemaName string, impact analyze.Impact) columnLink {
+	key := tableKey{schemaName, impact.Table}
+	table := m.tablesBySchemaAndName[key]
+	if impact.Kind != analyze.IndexImpact {
+		row := lo.IndexOf(lo.Map(table.ForeignKeys, func(fk inspect.ForeignKey, _ int) string { return fk.Name }), impact.Name)
+		return columnLink{table: key, tab: types.ForeignKeysTable, row: row}
+	}
+	if row := lo.IndexOf(lo.Map(table.Indexes, func(idx inspect.Index, _ int) string { return idx.Name }), impact.Name); row >= 0 {
+		return columnLink{table: key, tab: types.IndexesTable, row: row}
+	}
+	row := lo.IndexOf(lo.Map(table.Columns, func(col inspect.Column, _ int) string { return col.Name }), impact.Column)
+	return columnLink{table: key, tab: types.ColumnsTable, row: row}
+}
+
+func (m *model) impactView(width, height int) string {
+	c := m.impact
+	return c.nodes.view([]string{
+		styles.TitleStyle.Render(fmt.Sprintf("Impact of changing %s.%s", c.key.tableName, c.key.columnName)),
+		// atlas inspection reports tables only
+		styles.SubTitleStyle.Render("indexes and foreign keys breaking when the column is renamed or dropped, views and functions are not loaded so are not listed"),
+		"",
+		c.key.tableName + "." + c.key.columnName,
+	}, width, height)
+}
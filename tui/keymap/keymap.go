@@ -29,12 +29,124 @@ var (
 	)
 	Search = key.NewBinding(
 		key.WithKeys("/"),
-		key.WithHelp("/", "search"),
+		key.WithHelp("/", "filter chart"),
+	)
+	Sort = key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort chart"),
+	)
+	Group = key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "group tables"),
+	)
+	Describe = key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "tables summary"),
+	)
+	Refresh = key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "reload schema"),
+	)
+	History = key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "snapshots history"),
+	)
+	Envs = key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "switch env"),
+	)
+	Compare = key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "compare"),
+	)
+	Migrate = key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "plan migration"),
+	)
+	ApplyPlan = key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "apply plan"),
+	)
+	Migrations = key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "migrations"),
+	)
+	Lint = key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "lint migrations"),
+	)
+	Stats = key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "table stats"),
+	)
+	Query = key.NewBinding(
+		key.WithKeys("Q"),
+		key.WithHelp("Q", "query console"),
+	)
+	Profile = key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "profile column"),
+	)
+	Coverage = key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "index coverage"),
+	)
+	RedundantIndexes = key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "redundant indexes"),
+	)
+	Graph = key.NewBinding(
+		key.WithKeys("G"),
+		key.WithHelp("G", "foreign keys graph"),
+	)
+	Impact = key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "column impact"),
+	)
+	NextPage = key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "next page"),
+	)
+	PrevPage = key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "previous page"),
+	)
+	Open = key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open"),
+	)
+	Toggle = key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "toggle"),
+	)
+	ToggleAll = key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "toggle all"),
+	)
+	Write = key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "write"),
+	)
+	Select = key.NewBinding(
+		key.WithKeys(tea.KeyEnter.String()),
+		key.WithHelp("enter", "select"),
+	)
+	Diff = key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "diff"),
+	)
+	Back = key.NewBinding(
+		key.WithKeys(tea.KeyEscape.String()),
+		key.WithHelp("esc", "back"),
 	)
 	Help = key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "help"),
 	)
+	Cancel = key.NewBinding(
+		key.WithKeys(tea.KeyCtrlC.String()),
+		key.WithHelp("ctrl+c", "cancel loading"),
+	)
 	Quit = key.NewBinding(
 		key.WithKeys("q", tea.KeyEscape.String(), tea.KeyCtrlC.String()),
 		key.WithHelp("q", "quit"),
@@ -59,6 +171,8 @@ func (k keyMap) FullHelp() [][]key.Binding {
 		{Tab},
 		{Up, Down},
 		{Left, Right},
+		{Search, Sort, Group, Describe},
+		{Refresh, Cancel, History, Envs, Compare, Migrate, ApplyPlan, Migrations, Lint, Stats, Query, Profile, Coverage, RedundantIndexes, Graph, Impact},
 		{Help, Quit},
 	}
 }
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"strings"
+)
+
+// linkItem is a line of a link list
+type linkItem struct {
+	// section is the title the item is listed under, along with the items of the same section right after it
+	section string
+	// prefix is drawn ahead of the item, e.g. the branches of a tree
+	prefix string
+	label  string
+}
+
+// linkList is the body of the overlays listing where to go next, e.g. the indexes of a column.
+// Its items are listed in sections, and the one under the cursor is opened on select.
+type linkList struct {
+	items []linkItem
+	// empty is shown in place of the items when there are none
+	empty string
+	// action describes opening an item, in the help line
+	action string
+	// open opens the item of the given index, and tells whether it could, which closes the overlay
+	open   func(item int) bool
+	cursor int
+}
+
+func (m *model) updateLinkList(l *linkList, msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keymap.Back):
+		m.state.overlay = types.NoOverlay
+	case key.Matches(msg, keymap.Quit):
+		m.state.quitting = true
+		return tea.Quit
+	case len(l.items) == 0:
+		// nothing to act on
+	case key.Matches(msg, keymap.Up):
+		l.cursor = lo.Clamp(l.cursor-1, 0, len(l.items)-1)
+	case key.Matches(msg, keymap.Down):
+		l.cursor = lo.Clamp(l.cursor+1, 0, len(l.items)-1)
+	case key.Matches(msg, keymap.Select):
+		if l.open(l.cursor) {
+			m.state.overlay = types.NoOverlay
+		}
+	}
+	return nil
+}
+
+// view renders the header lines above the items, scrolled so that the cursor stays in sight
+func (l *linkList) view(header []string, width, height int) string {
+	lines := header
+	section := ""
+	cursorLine := 0
+	for i, item := range l.items {
+		if item.section != section {
+			section = item.section
+			lines = append(lines, "", styles.TitleStyle.Render(section))
+		}
+		if i == l.cursor {
+			cursorLine = len(lines)
+		}
+		lines = append(lines, item.prefix+lo.Ternary(i == l.cursor, styles.TitleStyle.Render("> "+item.label), "  "+item.label))
+	}
+	if len(l.items) == 0 {
+		lines = append(lines, "", l.empty)
+	}
+	help := styles.SubTitleStyle.Render(fmt.Sprintf("%s %s · %s close", keymap.Select.Help().Key, l.action, keymap.Back.Help().Key))
+
+	bodyHeight := max(0, height-lipgloss.Height(help))
+	offset := 0
+	if cursorLine >= bodyHeight {
+		offset = min(cursorLine-bodyHeight+1, len(lines))
+	}
+	body := lipgloss.NewStyle().Height(bodyHeight).MaxHeight(bodyHeight).Render(strings.Join(lines[offset:], "\n"))
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, body, help),
+	), true)
+}
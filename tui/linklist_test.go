@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"context"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reallyliri/atlastui/tui/types"
+	"strings"
+	"testing"
+)
+
+func TestLinkList(t *testing.T) {
+	m := newRootModel(context.Background(), "test", nil, runConfig{})
+	var opened []int
+	l := &linkList{
+		items: []linkItem{
+			{section: "first", label: "a"},
+			{section: "first", label: "b"},
+			{section: "second", prefix: "└─ ", label: "c"},
+		},
+		action: "go to",
+		open: func(item int) bool {
+			opened = append(opened, item)
+			return item != 0
+		},
+	}
+	m.state.overlay = types.GraphOverlay
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyUp},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyDown},
+		{Type: tea.KeyDown},
+		{Type: tea.KeyDown},
+	}
+	for _, msg := range keys {
+		m.updateLinkList(l, msg)
+	}
+	if len(opened) != 1 || opened[0] != 0 || m.state.overlay != types.GraphOverlay {
+		t.Fatalf("opened %v, overlay %v, want the first item opened and the overlay kept", opened, m.state.overlay)
+	}
+	if l.cursor != 2 {
+		t.Fatalf("cursor = %d, want the last item", l.cursor)
+	}
+	m.updateLinkList(l, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state.overlay != types.NoOverlay {
+		t.Errorf("overlay %v is not closed after opening an item", m.state.overlay)
+	}
+
+	if view := l.view([]string{"header"}, 80, 24); !strings.Contains(view, "second") || !strings.Contains(view, "└─ > c") {
+		t.Errorf("view misses the section or the cursor of the last item:\n%s", view)
+	}
+	// down to terminals too small to fit the help line
+	for _, size := range [][2]int{{40, 6}, {20, 3}, {10, 1}, {1, 0}} {
+		if view := l.view([]string{"header"}, size[0], size[1]); view == "" {
+			t.Errorf("empty view at %dx%d", size[0], size[1])
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/migrate"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"strings"
+	"time"
+)
+
+// LintLoader runs 'atlas migrate lint' over the migration dir of the env
+type LintLoader func(ctx context.Context, env string) (*migrate.Lint, error)
+
+// migrationLint holds the last lint run, kept while the panel is closed as linting replays the dir on a dev database
+type migrationLint struct {
+	lint      *migrate.Lint
+	loaded    bool
+	err       error
+	cancel    context.CancelFunc
+	startedAt time.Time
+	cursor    int
+	details   viewport.Model
+}
+
+type lintLoadedMsg struct {
+	target *migrationLint
+	lint   *migrate.Lint
+	err    error
+}
+
+// WithMigrationLint allows linting the migration dir, showing the diagnostics along with their files and tables
+func WithMigrationLint(load LintLoader) Option {
+	return func(c *runConfig) {
+		c.lint = load
+	}
+}
+
+func (m *model) openLint() tea.Cmd {
+	if m.lintLoader == nil {
+		m.state.bannerErr = fmt.Errorf("no migration dir to lint, set --dir or use an env")
+		return nil
+	}
+	if m.lint != nil {
+		m.state.overlay = types.LintOverlay
+		return nil
+	}
+	return m.startLint()
+}
+
+func (m *model) startLint() tea.Cmd {
+	ctx, cancel := context.WithCancel(m.ctx)
+	l := &migrationLint{
+		cancel:    cancel,
+		startedAt: time.Now(),
+		details:   viewport.New(0, 0),
+	}
+	m.lint = l
+	m.state.overlay = types.LintOverlay
+	load, env := m.lintLoader, m.state.env
+	return func() tea.Msg {
+		defer cancel()
+		lint, err := load(ctx, env)
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("cancelled after %s", formatElapsed(time.Since(l.startedAt)))
+		}
+		return lintLoadedMsg{target: l, lint: lint, err: err}
+	}
+}
+
+func (m *model) onLintLoaded(msg lintLoadedMsg) {
+	l := m.lint
+	if l == nil || l != msg.target {
+		return
+	}
+	l.cancel = nil
+	l.loaded = true
+	l.err = msg.err
+	l.lint = msg.lint
+	if l.err == nil {
+		m.onDiagnosticSelected()
+		if m.loaded() {
+			m.refreshTablesList()
+		}
+	}
+}
+
+func (m *model) onDiagnosticSelected() {
+	l := m.lint
+	if len(l.lint.Diagnostics) == 0 {
+		return
+	}
+	d := l.lint.Diagnostics[l.cursor]
+	lines := []string{
+		styles.TitleStyle.Render(fmt.Sprintf("%s %s", d.Code, d.Category)),
+		lipgloss.NewStyle().Width(l.details.Width).Render(d.Text),
+		"",
+		styles.SubTitleStyle.Render(fmt.Sprintf("file: %s", d.File)),
+		styles.SubTitleStyle.Render(fmt.Sprintf("table: %s", lo.Ternary(d.Table == "", "-", d.Table))),
+	}
+	if d.Report != "" {
+		lines = append(lines, styles.SubTitleStyle.Render(fmt.Sprintf("report: %s", d.Report)))
+	}
+	for _, fix := range d.Fixes {
+		lines = append(lines, styles.BreadcrumbsTitleStyle.Render(fmt.Sprintf("fix: %s", fix)))
+	}
+	if d.Statement != "" {
+		lines = append(lines, "", highlightSQL(d.Statement))
+	}
+	l.details.SetContent(strings.Join(lines, "\n"))
+	l.details.GotoTop()
+}
+
+func (m *model) closeLint() {
+	if m.lint != nil && m.lint.cancel != nil {
+		m.lint.cancel()
+		// a cancelled run has nothing worth keeping
+		m.lint = nil
+	}
+	m.state.overlay = types.NoOverlay
+}
+
+func (m *model) updateLint(msg tea.KeyMsg) tea.Cmd {
+	l := m.lint
+	switch {
+	case l.cancel != nil && key.Matches(msg, keymap.Cancel):
+		l.cancel()
+	case key.Matches(msg, keymap.Back):
+		m.closeLint()
+	case key.Matches(msg, keymap.Quit):
+		m.state.quitting = true
+		return tea.Quit
+	case l.cancel == nil && key.Matches(msg, keymap.Refresh):
+		return m.startLint()
+	case l.lint == nil || len(l.lint.Diagnostics) == 0:
+		// nothing to act on yet
+	case key.Matches(msg, keymap.Up):
+		l.cursor = lo.Clamp(l.cursor-1, 0, len(l.lint.Diagnostics)-1)
+		m.onDiagnosticSelected()
+	case key.Matches(msg, keymap.Down):
+		l.cursor = lo.Clamp(l.cursor+1, 0, len(l.lint.Diagnostics)-1)
+		m.onDiagnosticSelected()
+	case key.Matches(msg, keymap.Select):
+		d := l.lint.Diagnostics[l.cursor]
+		if d.Table == "" {
+			m.state.bannerErr = fmt.Errorf("the diagnostic is not bound to a table")
+			return nil
+		}
+		if !m.selectTable(lo.Ternary(d.Schema == "", m.state.selectedSchema, d.Schema), d.Table) {
+			m.state.bannerErr = fmt.Errorf("table %s is not in the inspected schema", d.Table)
+			return nil
+		}
+		m.state.overlay = types.NoOverlay
+	case key.Matches(msg, keymap.Open):
+		return m.openMigrationFile(l.lint.Diagnostics[l.cursor].File)
+	default:
+		var cmd tea.Cmd
+		l.details, cmd = l.details.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func lintCategoryMarker(category migrate.LintCategory) string {
+	switch category {
+	case migrate.Destructive:
+		return "✗ "
+	case migrate.DataDependent, migrate.BackwardIncompatible:
+		return warningMarker + " "
+	default:
+		return "• "
+	}
+}
+
+func (m *model) lintView(width, height int) string {
+	l := m.lint
+	switch {
+	case l.err != nil:
+		return withBorder(centeredBox(width, height,
+			styles.ErrorStyle.Render("Failed to lint the migration dir"), "", styles.ErrorStyle.Render(l.err.Error()),
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to retry", keymap.Refresh.Help().Key)),
+		), true)
+	case !l.loaded:
+		return withBorder(centeredBox(width, height,
+			fmt.Sprintf("%s Linting migration dir... %s", m.vms.globe.View(), formatElapsed(time.Since(l.startedAt))),
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to cancel", keymap.Cancel.Help().Key)),
+		), true)
+	case len(l.lint.Diagnostics) == 0 && len(l.lint.Errors) == 0:
+		return withBorder(centeredBox(width, height,
+			fmt.Sprintf("No lint diagnostics in %s", l.lint.DirURL),
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to close", keymap.Back.Help().Key)),
+		), true)
+	}
+
+	counts := lo.CountValuesBy(l.lint.Diagnostics, func(d migrate.Diagnostic) migrate.LintCategory { return d.Category })
+	summary := []string{fmt.Sprintf("%d diagnostics", len(l.lint.Diagnostics))}
+	for _, category := range []migrate.LintCategory{migrate.Destructive, migrate.DataDependent, migrate.BackwardIncompatible, migrate.OtherLintCategory} {
+		if counts[category] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[category], category))
+		}
+	}
+	headerLines := []string{
+		styles.TitleStyle.Render(fmt.Sprintf("Lint of %s", l.lint.DirURL)),
+		styles.SubTitleStyle.Render(strings.Join(summary, " · ")),
+	}
+	headerLines = append(headerLines, lo.Map(l.lint.Errors, func(err string, _ int) string {
+		return styles.ErrorStyle.Render(firstLine(err))
+	})...)
+	header := lipgloss.JoinVertical(lipgloss.Left, headerLines...)
+	help := styles.SubTitleStyle.Render(fmt.Sprintf(
+		"%s show table · %s open file · %s re-lint · %s close",
+		keymap.Select.Help().Key, keymap.Open.Help().Key, keymap.Refresh.Help().Key, keymap.Back.Help().Key,
+	))
+
+	bodyHeight := height - lipgloss.Height(header) - lipgloss.Height(help)
+	listWidth := width / 2
+	lines := lo.Map(l.lint.Diagnostics, func(d migrate.Diagnostic, i int) string {
+		line := fmt.Sprintf("%s%s %s", lintCategoryMarker(d.Category), d.Code, d.File)
+		if d.Table != "" {
+			line += " · " + d.Table
+		}
+		return lo.Ternary(i == l.cursor, styles.TitleStyle.Render("> "+line), "  "+line)
+	})
+	offset := lo.Max([]int{0, l.cursor - bodyHeight + 1})
+	lines = lines[offset:lo.Min([]int{len(lines), offset + bodyHeight})]
+	list := lipgloss.NewStyle().Width(listWidth).Height(bodyHeight).MaxHeight(bodyHeight).Render(strings.Join(lines, "\n"))
+
+	l.details.Height = bodyHeight
+	if detailsWidth := width - listWidth - 1; l.details.Width != detailsWidth {
+		// the diagnostic text is wrapped to the details width
+		l.details.Width = detailsWidth
+		m.onDiagnosticSelected()
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, list, " ", l.details.View())
+
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, header, body, help),
+	), true)
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/format"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"io"
+	"strings"
+	"time"
+)
+
+const maxStderrLines = 10
+
+// Loader fetches the data to display, stderr receives any diagnostics output while loading
+type Loader func(ctx context.Context, stderr io.Writer) (*inspect.Data, error)
+
+// EnvLoader loads the schema of the given env of the project, regardless of the env currently displayed
+type EnvLoader func(ctx context.Context, env string, stderr io.Writer) (*inspect.Data, error)
+
+type dataLoadedMsg struct {
+	data    *inspect.Data
+	err     error
+	saveErr error
+}
+
+func (m *model) startLoad() tea.Cmd {
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancelLoad = cancel
+	m.stderr.Reset()
+	m.state.loading = true
+	m.state.loadStartedAt = time.Now()
+	m.state.loadErr = nil
+	load := m.load
+	// the env is fixed when the load starts, switching envs meanwhile starts another load
+	env := cmp.Or(m.state.pendingEnv, m.state.env)
+	if m.envs != nil {
+		loadEnv := m.envs.load
+		load = func(ctx context.Context, stderr io.Writer) (*inspect.Data, error) {
+			return loadEnv(ctx, env, stderr)
+		}
+	}
+	stderr := m.stderr
+	startedAt := m.state.loadStartedAt
+	return func() tea.Msg {
+		defer cancel()
+		data, err := load(ctx, stderr)
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("cancelled after %s", formatElapsed(time.Since(startedAt)))
+		}
+		if err != nil {
+			return dataLoadedMsg{err: err}
+		}
+		return dataLoadedMsg{data: data, saveErr: m.saveSnapshot(env, *data)}
+	}
+}
+
+func (m *model) onDataLoaded(msg dataLoadedMsg) {
+	m.state.loading = false
+	m.cancelLoad = nil
+	if msg.err == nil && len(msg.data.Schemas) == 0 {
+		msg.err = fmt.Errorf("no schema found")
+	}
+	switching := m.state.pendingEnv != "" && m.state.pendingEnv != m.state.env
+	if msg.err != nil && switching && m.loaded() {
+		// the current env is still displayed, so the failure is not worth a reload error
+		m.state.bannerErr = fmt.Errorf("failed to switch to env %s: %w", m.state.pendingEnv, msg.err)
+		m.onEnvLoaded(false)
+		return
+	}
+	envSwitched := m.onEnvLoaded(msg.err == nil)
+	if msg.err != nil {
+		m.state.loadErr = msg.err
+		return
+	}
+	m.state.loadErr = nil
+	m.state.bannerErr = msg.saveErr
+	m.state.loadedAt = time.Now()
+	m.state.viewing = ""
+	switch {
+	case envSwitched:
+		// comparing different envs is for the dedicated comparison view, not for highlighting
+		m.diff = inspect.Diff{}
+		m.state.diffTitle = ""
+		// the lint is of the migration dir of the previous env
+		m.lint = nil
+	case m.data != nil:
+		m.diff = inspect.Compare(*m.data, *msg.data)
+		m.state.diffTitle = fmt.Sprintf("reloaded at %s", m.state.loadedAt.Format(time.TimeOnly))
+	}
+	m.setData(*msg.data)
+	// the plan keeps highlighting its changes, it is not re-planned on reloads
+	m.applyPlanDiff()
+}
+
+// statusView reports on reloads of already displayed data, initial loading has its own view
+func (m *model) statusView() string {
+	switch {
+	case !m.loaded():
+		return ""
+	case m.state.loading && m.state.pendingEnv != "" && m.state.pendingEnv != m.state.env:
+		return styles.SubTitleStyle.Render(fmt.Sprintf("inspecting env %s... %s (%s to cancel)", m.state.pendingEnv, formatElapsed(time.Since(m.state.loadStartedAt)), keymap.Cancel.Help().Key))
+	case m.state.loading:
+		return styles.SubTitleStyle.Render(fmt.Sprintf("reloading... %s (%s to cancel)", formatElapsed(time.Since(m.state.loadStartedAt)), keymap.Cancel.Help().Key))
+	case m.state.loadErr != nil:
+		return styles.ErrorStyle.Render(fmt.Sprintf("reload failed: %s", firstLine(m.state.loadErr.Error())))
+	case m.state.bannerErr != nil:
+		return styles.ErrorStyle.Render(firstLine(m.state.bannerErr.Error()))
+	case m.state.viewing != "":
+		return styles.SubTitleStyle.Render(fmt.Sprintf("%s (%s to reload)", m.state.viewing, keymap.Refresh.Help().Key))
+	case m.state.diffTitle != "":
+		counts := m.diff.TablesCount()
+		changes := make([]string, 0, 3)
+		for _, change := range []inspect.Change{inspect.Added, inspect.Modified, inspect.Removed} {
+			if counts[change] > 0 {
+				changes = append(changes, fmt.Sprintf("%s%d %s", format.ChangeMarker(change), counts[change], change))
+			}
+		}
+		summary := "no changes"
+		if len(changes) > 0 {
+			summary = strings.Join(changes, format.TabsSeparator)
+		}
+		return styles.SubTitleStyle.Render(fmt.Sprintf("%s: %s", m.state.diffTitle, summary))
+	default:
+		return ""
+	}
+}
+
+func formatElapsed(d time.Duration) string {
+	return d.Truncate(100 * time.Millisecond).String()
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"strings"
+	"sync"
+)
+
+// logTail is a concurrency safe writer keeping only the last lines written to it
+type logTail struct {
+	mu       sync.Mutex
+	lines    []string
+	partial  string
+	maxLines int
+}
+
+func newLogTail(maxLines int) *logTail {
+	return &logTail{maxLines: maxLines}
+}
+
+func (l *logTail) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	parts := strings.Split(l.partial+string(p), "\n")
+	l.partial = parts[len(parts)-1]
+	for _, line := range parts[:len(parts)-1] {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			l.lines = append(l.lines, line)
+		}
+	}
+	if len(l.lines) > l.maxLines {
+		l.lines = l.lines[len(l.lines)-l.maxLines:]
+	}
+	return len(p), nil
+}
+
+func (l *logTail) Lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lines := append([]string{}, l.lines...)
+	if l.partial != "" {
+		lines = append(lines, l.partial)
+	}
+	return lines
+}
+
+func (l *logTail) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = nil
+	l.partial = ""
+}
@@ -0,0 +1,301 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/migrate"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"strings"
+	"time"
+)
+
+// MigrationStatusLoader runs 'atlas migrate status' for the migration dir of the env against its database
+type MigrationStatusLoader func(ctx context.Context, env string) (*migrate.Status, error)
+
+// migrationDir is the status of the migration dir, browsing its files and the tables they touch
+type migrationDir struct {
+	status        *migrate.Status
+	loaded        bool
+	err           error
+	cancel        context.CancelFunc
+	startedAt     time.Time
+	cursor        int
+	tables        []string
+	tableCursor   int
+	tablesFocused bool
+	// selectFile is the file to select once loaded, rather than the next file to apply
+	selectFile string
+	// returnTo is the overlay the panel was opened from
+	returnTo types.Overlay
+	sqlView  viewport.Model
+}
+
+type migrationStatusLoadedMsg struct {
+	target *migrationDir
+	status *migrate.Status
+	err    error
+}
+
+// WithMigrationStatus allows browsing the migration dir, along with which of its files were applied
+func WithMigrationStatus(load MigrationStatusLoader) Option {
+	return func(c *runConfig) {
+		c.migrationStatus = load
+	}
+}
+
+func (m *model) openMigrationDir() tea.Cmd {
+	if m.migrationStatus == nil {
+		m.state.bannerErr = fmt.Errorf("no migration dir, set --dir or use an env")
+		return nil
+	}
+	return m.startMigrationStatus()
+}
+
+func (m *model) startMigrationStatus() tea.Cmd {
+	ctx, cancel := context.WithCancel(m.ctx)
+	d := &migrationDir{
+		cancel:    cancel,
+		startedAt: time.Now(),
+		sqlView:   viewport.New(0, 0),
+	}
+	if prev := m.migrationDir; prev != nil {
+		d.returnTo = prev.returnTo
+		if prev.status != nil && len(prev.status.Files) > 0 {
+			d.selectFile = prev.status.Files[prev.cursor].Name
+		}
+	}
+	m.migrationDir = d
+	m.state.overlay = types.MigrationStatusOverlay
+	load, env := m.migrationStatus, m.state.env
+	return func() tea.Msg {
+		defer cancel()
+		status, err := load(ctx, env)
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("cancelled after %s", formatElapsed(time.Since(d.startedAt)))
+		}
+		return migrationStatusLoadedMsg{target: d, status: status, err: err}
+	}
+}
+
+func (m *model) onMigrationStatusLoaded(msg migrationStatusLoadedMsg) {
+	d := m.migrationDir
+	if d == nil || d != msg.target {
+		return
+	}
+	d.cancel = nil
+	d.loaded = true
+	if msg.err != nil {
+		d.err = msg.err
+		return
+	}
+	d.status = msg.status
+	// start from the next file to apply, or the last applied one when all are
+	d.cursor = lo.Max([]int{0, len(d.status.Files) - 1})
+	if _, idx, ok := lo.FindIndexOf(d.status.Files, func(file migrate.File) bool { return !file.Applied }); ok {
+		d.cursor = idx
+	}
+	if _, idx, ok := lo.FindIndexOf(d.status.Files, func(file migrate.File) bool { return file.Name == d.selectFile }); ok {
+		d.cursor = idx
+	}
+	m.onMigrationFileSelected()
+}
+
+func (m *model) onMigrationFileSelected() {
+	d := m.migrationDir
+	d.tables = nil
+	d.tableCursor = 0
+	if len(d.status.Files) == 0 {
+		return
+	}
+	file := d.status.Files[d.cursor]
+	lines := make([]string, 0)
+	if file.Applied {
+		applied := fmt.Sprintf("applied at %s, took %s", file.ExecutedAt.Local().Format(time.DateTime), file.ExecutionTime)
+		if file.Partial() {
+			applied += fmt.Sprintf(", %d of %d statements", file.AppliedStmts, file.TotalStmts)
+		}
+		lines = append(lines, styles.SubTitleStyle.Render(applied))
+	}
+	if file.Error != "" {
+		lines = append(lines, styles.ErrorStyle.Render(file.Error))
+	}
+	sql, err := d.status.ReadFile(file)
+	if err != nil {
+		lines = append(lines, styles.ErrorStyle.Render(err.Error()))
+	} else {
+		d.tables = migrate.Tables(sql)
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, highlightSQL(strings.TrimSpace(sql)))
+	}
+	d.sqlView.SetContent(strings.Join(lines, "\n"))
+	d.sqlView.GotoTop()
+}
+
+// openMigrationFile opens the migration dir panel on the given file, returning to the current overlay once closed
+func (m *model) openMigrationFile(name string) tea.Cmd {
+	returnTo := m.state.overlay
+	cmd := m.openMigrationDir()
+	if m.migrationDir != nil {
+		m.migrationDir.selectFile = name
+		m.migrationDir.returnTo = returnTo
+	}
+	return cmd
+}
+
+func (m *model) closeMigrationDir() {
+	returnTo := types.NoOverlay
+	if d := m.migrationDir; d != nil {
+		if d.cancel != nil {
+			d.cancel()
+		}
+		returnTo = d.returnTo
+	}
+	m.migrationDir = nil
+	m.state.overlay = returnTo
+}
+
+func (m *model) updateMigrationDir(msg tea.KeyMsg) tea.Cmd {
+	d := m.migrationDir
+	switch {
+	case d.cancel != nil && key.Matches(msg, keymap.Cancel):
+		d.cancel()
+	case key.Matches(msg, keymap.Back):
+		m.closeMigrationDir()
+	case key.Matches(msg, keymap.Quit):
+		m.state.quitting = true
+		return tea.Quit
+	case d.cancel == nil && key.Matches(msg, keymap.Refresh):
+		return m.startMigrationStatus()
+	case d.status == nil || len(d.status.Files) == 0:
+		// nothing to act on yet
+	case key.Matches(msg, keymap.Tab):
+		d.tablesFocused = !d.tablesFocused && len(d.tables) > 0
+	case d.tablesFocused && key.Matches(msg, keymap.Up):
+		d.tableCursor = lo.Clamp(d.tableCursor-1, 0, len(d.tables)-1)
+	case d.tablesFocused && key.Matches(msg, keymap.Down):
+		d.tableCursor = lo.Clamp(d.tableCursor+1, 0, len(d.tables)-1)
+	case d.tablesFocused && key.Matches(msg, keymap.Select):
+		table := d.tables[d.tableCursor]
+		if !m.selectTable(m.state.selectedSchema, table) {
+			m.state.bannerErr = fmt.Errorf("table %s is not in the inspected schema", table)
+			return nil
+		}
+		m.closeMigrationDir()
+		m.state.overlay = types.NoOverlay
+	case key.Matches(msg, keymap.Up):
+		d.cursor = lo.Clamp(d.cursor-1, 0, len(d.status.Files)-1)
+		m.onMigrationFileSelected()
+	case key.Matches(msg, keymap.Down):
+		d.cursor = lo.Clamp(d.cursor+1, 0, len(d.status.Files)-1)
+		m.onMigrationFileSelected()
+	case key.Matches(msg, keymap.Select):
+		d.tablesFocused = len(d.tables) > 0
+	default:
+		var cmd tea.Cmd
+		d.sqlView, cmd = d.sqlView.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func migrationFileMarker(file migrate.File) string {
+	switch {
+	case file.Error != "":
+		return "✗ "
+	case file.Partial():
+		return "◐ "
+	case file.Applied:
+		return "✓ "
+	default:
+		return "○ "
+	}
+}
+
+func (m *model) migrationDirView(width, height int) string {
+	d := m.migrationDir
+	switch {
+	case d.err != nil:
+		return withBorder(centeredBox(width, height,
+			styles.ErrorStyle.Render("Failed to get the migration status"), "", styles.ErrorStyle.Render(d.err.Error()),
+		), true)
+	case !d.loaded:
+		return withBorder(centeredBox(width, height,
+			fmt.Sprintf("%s Getting migration status... %s", m.vms.globe.View(), formatElapsed(time.Since(d.startedAt))),
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to cancel", keymap.Cancel.Help().Key)),
+		), true)
+	case len(d.status.Files) == 0:
+		return withBorder(centeredBox(width, height,
+			fmt.Sprintf("There are no migration files in %s", d.status.DirURL),
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to close", keymap.Back.Help().Key)),
+		), true)
+	}
+
+	s := d.status
+	applied := lo.CountBy(s.Files, func(file migrate.File) bool { return file.Applied })
+	summary := fmt.Sprintf("%s · current %s · next %s · %d applied · %d pending",
+		lo.Ternary(s.State == "", "unknown", s.State), lo.Ternary(s.Current == "", "-", s.Current), lo.Ternary(s.Next == "", "-", s.Next),
+		applied, len(s.Files)-applied,
+	)
+	headerLines := []string{
+		styles.TitleStyle.Render(fmt.Sprintf("Migrations of %s", s.DirURL)),
+		styles.SubTitleStyle.Render(summary),
+	}
+	if s.Error != "" {
+		headerLines = append(headerLines, styles.ErrorStyle.Render(s.Error))
+	}
+	header := lipgloss.JoinVertical(lipgloss.Left, headerLines...)
+	help := styles.SubTitleStyle.Render(fmt.Sprintf(
+		"%s switch to tables · %s show table · %s reload · %s close",
+		keymap.Tab.Help().Key, keymap.Select.Help().Key, keymap.Refresh.Help().Key, keymap.Back.Help().Key,
+	))
+
+	bodyHeight := lo.Max([]int{0, height - lipgloss.Height(header) - lipgloss.Height(help)})
+	listWidth := width / 3
+	// the touched tables take a third of the body at most, and at least their title when there are any
+	tablesHeight := 0
+	if len(d.tables) > 0 {
+		tablesHeight = lo.Max([]int{1, lo.Min([]int{len(d.tables) + 1, bodyHeight / 3})})
+	}
+	filesHeight := lo.Max([]int{1, bodyHeight - tablesHeight})
+
+	files := lo.Map(s.Files, func(file migrate.File, i int) string {
+		line := migrationFileMarker(file) + lo.Ternary(file.Description == "", file.Version, fmt.Sprintf("%s %s", file.Version, file.Description))
+		return lo.Ternary(i == d.cursor, lo.Ternary(d.tablesFocused, "> "+line, styles.TitleStyle.Render("> "+line)), "  "+line)
+	})
+	// keep the cursor in sight when there are more files than lines
+	offset := lo.Max([]int{0, d.cursor - filesHeight + 1})
+	files = files[offset:lo.Min([]int{len(files), offset + filesHeight})]
+	list := lipgloss.NewStyle().Width(listWidth).Height(filesHeight).MaxHeight(filesHeight).Render(strings.Join(files, "\n"))
+
+	if tablesHeight > 0 {
+		tables := lo.Map(d.tables, func(table string, i int) string {
+			return lo.Ternary(d.tablesFocused && i == d.tableCursor, styles.TitleStyle.Render("> "+table), "  "+table)
+		})
+		// the title takes the first line, the rest scroll with the cursor
+		visible := tablesHeight - 1
+		offset = lo.Clamp(d.tableCursor-visible+1, 0, len(tables)-visible)
+		tables = append([]string{styles.SubTitleStyle.Render(fmt.Sprintf("Touched tables (%d)", len(d.tables)))}, tables[offset:offset+visible]...)
+		list = lipgloss.JoinVertical(lipgloss.Left,
+			list,
+			lipgloss.NewStyle().Width(listWidth).Height(tablesHeight).MaxHeight(tablesHeight).Render(strings.Join(tables, "\n")),
+		)
+	}
+
+	d.sqlView.Width = width - listWidth - 1
+	d.sqlView.Height = bodyHeight
+	body := lipgloss.JoinHorizontal(lipgloss.Top, list, " ", d.sqlView.View())
+
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, header, body, help),
+	), true)
+}
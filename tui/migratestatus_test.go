@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"context"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/reallyliri/atlastui/migrate"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMigrationDirView(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"20240101000000_empty.sql":     "",
+		"20240102000000_extension.sql": "CREATE EXTENSION IF NOT EXISTS pgcrypto;",
+		"20240103000000_tables.sql":    "CREATE TABLE a (id int);\nCREATE TABLE b (id int);\nALTER TABLE a ADD COLUMN c_id int;\nDROP TABLE c;",
+	}
+	for name, sql := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(sql), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	status := &migrate.Status{DirURL: "file://" + dir, Files: []migrate.File{
+		{Name: "20240101000000_empty.sql", Version: "20240101000000", Applied: true},
+		{Name: "20240102000000_extension.sql", Version: "20240102000000", Applied: true},
+		{Name: "20240103000000_tables.sql", Version: "20240103000000"},
+		{Name: "20240104000000_missing.sql", Version: "20240104000000"},
+	}}
+
+	tests := []struct {
+		name        string
+		selectFile  string
+		tableCursor int
+		wantCursor  int
+		wantTables  []string
+		wantLines   []string
+	}{
+		{
+			name:       "next file to apply",
+			wantCursor: 2,
+			wantTables: []string{"a", "b", "c"},
+			wantLines:  []string{"Touched tables (3)", "  a", "  b", "  c"},
+		},
+		{name: "empty file", selectFile: "20240101000000_empty.sql", wantCursor: 0},
+		{name: "no tables", selectFile: "20240102000000_extension.sql", wantCursor: 1},
+		{name: "missing file", selectFile: "20240104000000_missing.sql", wantCursor: 3, wantLines: []string{"failed to read migration file"}},
+		{
+			name:        "last table",
+			selectFile:  "20240103000000_tables.sql",
+			tableCursor: 2,
+			wantCursor:  2,
+			wantTables:  []string{"a", "b", "c"},
+			wantLines:   []string{"> c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRootModel(context.Background(), "test", nil, runConfig{})
+			d := &migrationDir{selectFile: tt.selectFile, sqlView: viewport.New(0, 0)}
+			m.migrationDir = d
+			m.onMigrationStatusLoaded(migrationStatusLoadedMsg{target: d, status: status})
+			if d.cursor != tt.wantCursor {
+				t.Errorf("cursor = %d, want %d", d.cursor, tt.wantCursor)
+			}
+			if !reflect.DeepEqual(d.tables, tt.wantTables) {
+				t.Errorf("tables = %v, want %v", d.tables, tt.wantTables)
+			}
+			d.tableCursor = tt.tableCursor
+			d.tablesFocused = tt.tableCursor > 0
+
+			view := m.migrationDirView(80, 24)
+			for _, want := range append([]string{"2 applied · 2 pending"}, tt.wantLines...) {
+				if !strings.Contains(view, want) {
+					t.Errorf("view is missing %q:\n%s", want, view)
+				}
+			}
+			if len(tt.wantTables) == 0 && strings.Contains(view, "Touched tables") {
+				t.Errorf("view lists touched tables of a file without any:\n%s", view)
+			}
+			// down to terminals too small to fit the header
+			for _, size := range [][2]int{{40, 6}, {20, 3}, {10, 1}, {1, 0}} {
+				if view := m.migrationDirView(size[0], size[1]); view == "" {
+					t.Errorf("empty view at %dx%d", size[0], size[1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package tui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/migrate"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"io"
+	"strings"
+	"time"
+)
+
+// otherStatementsTitle is shown for statements not bound to any table, e.g. creating a schema
+const otherStatementsTitle = "(other)"
+
+// PlanLoader plans the sql migrating the from schema to match the to schema. Each is named by its env or by the
+// compare source, and is empty for the inspected schema when it is not an env
+type PlanLoader func(ctx context.Context, from, to string, stderr io.Writer) (string, error)
+
+// MigrationWriter writes the sql as a new file into the migration dir of --dir, or else of the env, returning the file path.
+// An empty env is of the inspected schema
+type MigrationWriter func(ctx context.Context, env, sql string) (string, error)
+
+type migrationsConfig struct {
+	plan  PlanLoader
+	write MigrationWriter
+}
+
+// migrationPlan is the sql migrating one compared schema (from) to match the other (to), an empty name is the current schema
+type migrationPlan struct {
+	from string
+	to   string
+	// statements are kept in the planned order, groups are what is listed and selected
+	statements []migrate.Statement
+	groups     []migrate.Group
+	selected   map[int]bool
+	cursor     int
+	loaded     bool
+	err        error
+	cancel     context.CancelFunc
+	startedAt  time.Time
+	writing    bool
+	written    string
+	sqlView    viewport.Model
+}
+
+type planLoadedMsg struct {
+	target *migrationPlan
+	sql    string
+	err    error
+}
+
+type migrationWrittenMsg struct {
+	target *migrationPlan
+	path   string
+	err    error
+}
+
+// WithMigrationPlans allows planning the migration between compared envs, and writing it as a migration file
+func WithMigrationPlans(plan PlanLoader, write MigrationWriter) Option {
+	return func(c *runConfig) {
+		c.migrations = &migrationsConfig{plan: plan, write: write}
+	}
+}
+
+func (m *model) startPlan() tea.Cmd {
+	if m.migrations == nil {
+		m.state.bannerErr = fmt.Errorf("migration planning is not available")
+		return nil
+	}
+	// the compared env is migrated to match the current one, while the current schema is migrated to match the compare source
+	from, to := m.compare.env, m.state.env
+	if m.compare.source {
+		from, to = m.state.env, m.compare.env
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	p := &migrationPlan{
+		from:      from,
+		to:        to,
+		selected:  make(map[int]bool),
+		cancel:    cancel,
+		startedAt: time.Now(),
+	}
+	m.plan = p
+	m.state.overlay = types.MigrationOverlay
+	plan := m.migrations.plan
+	return func() tea.Msg {
+		defer cancel()
+		sql, err := plan(ctx, p.from, p.to, nil)
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("cancelled after %s", formatElapsed(time.Since(p.startedAt)))
+		}
+		return planLoadedMsg{target: p, sql: sql, err: err}
+	}
+}
+
+func (m *model) onPlanLoaded(msg planLoadedMsg) {
+	p := m.plan
+	if p == nil || p != msg.target {
+		return
+	}
+	p.cancel = nil
+	p.loaded = true
+	if msg.err != nil {
+		p.err = msg.err
+		return
+	}
+	p.statements = migrate.ParseStatements(msg.sql)
+	p.groups = migrate.GroupByTable(p.statements)
+	for i := range p.groups {
+		p.selected[i] = true
+	}
+	p.sqlView = viewport.New(0, 0)
+	m.onPlanGroupSelected()
+}
+
+func (m *model) onPlanGroupSelected() {
+	p := m.plan
+	if len(p.groups) == 0 {
+		return
+	}
+	p.sqlView.SetContent(migrate.Format(p.groups[p.cursor].Statements))
+	p.sqlView.GotoTop()
+}
+
+func (m *model) closePlan() {
+	if m.plan != nil && m.plan.cancel != nil {
+		m.plan.cancel()
+	}
+	m.plan = nil
+	m.state.overlay = types.CompareOverlay
+}
+
+// selectedStatements keeps the planned order, as statements of different tables may depend on each other,
+// e.g. a foreign key added to one table before the table it references is created
+func (p *migrationPlan) selectedStatements() []migrate.Statement {
+	return lo.Filter(p.statements, func(stmt migrate.Statement, _ int) bool {
+		_, i, ok := lo.FindIndexOf(p.groups, func(group migrate.Group) bool {
+			return group.Holds(stmt)
+		})
+		return ok && p.selected[i]
+	})
+}
+
+func (m *model) writeMigration() tea.Cmd {
+	p := m.plan
+	statements := p.selectedStatements()
+	if len(statements) == 0 {
+		m.state.bannerErr = fmt.Errorf("select at least one table to write a migration")
+		return nil
+	}
+	p.writing = true
+	write := m.migrations.write
+	ctx := m.ctx
+	return func() tea.Msg {
+		path, err := write(ctx, p.from, migrate.Format(statements))
+		return migrationWrittenMsg{target: p, path: path, err: err}
+	}
+}
+
+func (m *model) onMigrationWritten(msg migrationWrittenMsg) {
+	p := msg.target
+	p.writing = false
+	if msg.err != nil {
+		m.state.bannerErr = msg.err
+		return
+	}
+	p.written = msg.path
+}
+
+func (m *model) updatePlan(msg tea.KeyMsg) tea.Cmd {
+	p := m.plan
+	switch {
+	case p.cancel != nil && key.Matches(msg, keymap.Cancel):
+		p.cancel()
+	case key.Matches(msg, keymap.Back):
+		m.closePlan()
+	case key.Matches(msg, keymap.Quit):
+		m.state.quitting = true
+		return tea.Quit
+	case len(p.groups) == 0 || p.writing:
+		// nothing to act on yet
+	case key.Matches(msg, keymap.Up):
+		p.cursor = lo.Clamp(p.cursor-1, 0, len(p.groups)-1)
+		m.onPlanGroupSelected()
+	case key.Matches(msg, keymap.Down):
+		p.cursor = lo.Clamp(p.cursor+1, 0, len(p.groups)-1)
+		m.onPlanGroupSelected()
+	case key.Matches(msg, keymap.Toggle):
+		p.selected[p.cursor] = !p.selected[p.cursor]
+		p.written = ""
+	case key.Matches(msg, keymap.ToggleAll):
+		all := len(lo.PickBy(p.selected, func(_ int, selected bool) bool { return selected })) == len(p.groups)
+		for i := range p.groups {
+			p.selected[i] = !all
+		}
+		p.written = ""
+	case key.Matches(msg, keymap.Write):
+		return m.writeMigration()
+	default:
+		var cmd tea.Cmd
+		p.sqlView, cmd = p.sqlView.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *model) planView(width, height int) string {
+	p := m.plan
+	from, to := cmp.Or(p.from, currentSourceTitle), cmp.Or(p.to, currentSourceTitle)
+	switch {
+	case p.err != nil:
+		return withBorder(centeredBox(width, height,
+			styles.ErrorStyle.Render(fmt.Sprintf("Failed to plan migration of %s to %s", from, to)), "", styles.ErrorStyle.Render(p.err.Error()),
+		), true)
+	case !p.loaded:
+		return withBorder(centeredBox(width, height,
+			fmt.Sprintf("%s Planning migration of %s to %s... %s", m.vms.globe.View(), from, to, formatElapsed(time.Since(p.startedAt))),
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to cancel", keymap.Cancel.Help().Key)),
+		), true)
+	case len(p.groups) == 0:
+		return withBorder(centeredBox(width, height,
+			fmt.Sprintf("%s and %s are synced, there is nothing to migrate", from, to),
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to go back", keymap.Back.Help().Key)),
+		), true)
+	}
+
+	statements := p.selectedStatements()
+	var status string
+	switch {
+	case p.writing:
+		status = fmt.Sprintf("%s writing migration...", m.vms.globe.View())
+	case p.written != "":
+		status = styles.BreadcrumbsTitleStyle.Render(fmt.Sprintf("migration written to %s", p.written))
+	default:
+		status = styles.SubTitleStyle.Render(fmt.Sprintf("%d of %d statements selected", len(statements), len(p.statements)))
+	}
+	header := lipgloss.JoinVertical(lipgloss.Left,
+		styles.TitleStyle.Render(fmt.Sprintf("Migration of %s to match %s", from, to)),
+		status,
+	)
+	help := styles.SubTitleStyle.Render(fmt.Sprintf(
+		"%s toggle · %s toggle all · %s write migration file · %s back",
+		keymap.Toggle.Help().Key, keymap.ToggleAll.Help().Key, keymap.Write.Help().Key, keymap.Back.Help().Key,
+	))
+
+	bodyHeight := height - lipgloss.Height(header) - lipgloss.Height(help)
+	listWidth := width / 3
+	lines := lo.Map(p.groups, func(group migrate.Group, i int) string {
+		line := fmt.Sprintf("%s %s (%d)",
+			lo.Ternary(p.selected[i], "[x]", "[ ]"),
+			lo.Ternary(group.Table == "", otherStatementsTitle, group.Name()),
+			len(group.Statements),
+		)
+		return lo.Ternary(i == p.cursor, styles.TitleStyle.Render("> "+line), "  "+line)
+	})
+	// keep the cursor in sight when there are more tables than lines
+	offset := lo.Max([]int{0, p.cursor - bodyHeight + 1})
+	lines = lines[offset:lo.Min([]int{len(lines), offset + bodyHeight})]
+	list := lipgloss.NewStyle().Width(listWidth).Height(bodyHeight).MaxHeight(bodyHeight).Render(strings.Join(lines, "\n"))
+
+	p.sqlView.Width = width - listWidth - 1
+	p.sqlView.Height = bodyHeight
+	body := lipgloss.JoinHorizontal(lipgloss.Top, list, " ", p.sqlView.View())
+
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, header, body, help),
+	), true)
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestSelectedStatements(t *testing.T) {
+	plan := `-- Create "a" table
+CREATE TABLE "a" ("id" integer NOT NULL, "b_id" integer NOT NULL);
+-- Create "b" table
+CREATE TABLE "b" ("id" integer NOT NULL, PRIMARY KEY ("id"));
+-- Modify "a" table
+ALTER TABLE "a" ADD CONSTRAINT "a_b" FOREIGN KEY ("b_id") REFERENCES "b" ("id");
+-- Create "c" table
+CREATE TABLE "c" ("id" integer NOT NULL);
+`
+	tests := []struct {
+		name     string
+		selected []string
+		want     []string
+	}{
+		{
+			name:     "all tables",
+			selected: []string{"a", "b", "c"},
+			want: []string{
+				`CREATE TABLE "a" ("id" integer NOT NULL, "b_id" integer NOT NULL);`,
+				`CREATE TABLE "b" ("id" integer NOT NULL, PRIMARY KEY ("id"));`,
+				`ALTER TABLE "a" ADD CONSTRAINT "a_b" FOREIGN KEY ("b_id") REFERENCES "b" ("id");`,
+				`CREATE TABLE "c" ("id" integer NOT NULL);`,
+			},
+		},
+		{
+			name:     "referencing tables",
+			selected: []string{"a", "b"},
+			want: []string{
+				`CREATE TABLE "a" ("id" integer NOT NULL, "b_id" integer NOT NULL);`,
+				`CREATE TABLE "b" ("id" integer NOT NULL, PRIMARY KEY ("id"));`,
+				`ALTER TABLE "a" ADD CONSTRAINT "a_b" FOREIGN KEY ("b_id") REFERENCES "b" ("id");`,
+			},
+		},
+		{
+			name:     "single table",
+			selected: []string{"c"},
+			want:     []string{`CREATE TABLE "c" ("id" integer NOT NULL);`},
+		},
+		{
+			name: "none",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRootModel(context.Background(), "test", nil, runConfig{})
+			p := &migrationPlan{selected: make(map[int]bool)}
+			m.plan = p
+			m.onPlanLoaded(planLoadedMsg{target: p, sql: plan})
+			for i, group := range p.groups {
+				p.selected[i] = false
+				for _, table := range tt.selected {
+					if group.Table == table {
+						p.selected[i] = true
+					}
+				}
+			}
+			var got []string
+			for _, stmt := range p.selectedStatements() {
+				got = append(got, stmt.SQL)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectedStatements()\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
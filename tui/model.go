@@ -7,13 +7,16 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	chart "github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/reallyliri/atlastui/analyze"
 	"github.com/reallyliri/atlastui/inspect"
 	"github.com/reallyliri/atlastui/tui/format"
 	"github.com/reallyliri/atlastui/tui/keymap"
 	"github.com/reallyliri/atlastui/tui/types"
 	"github.com/samber/lo"
 	"strings"
+	"time"
 )
 
 /*
@@ -28,33 +31,87 @@ type tableKey struct {
 }
 
 type modelState struct {
-	selectedSchema string
-	selectedTable  string
-	selectedTab    types.TableDetailsSection
-	focused        types.FocusedComponent
-	quitting       bool
-	termWidth      int
-	termHeight     int
+	selectedSchema   string
+	selectedTable    string
+	selectedTab      types.TableDetailsSection
+	focused          types.FocusedComponent
+	quitting         bool
+	termWidth        int
+	termHeight       int
+	loading          bool
+	loadStartedAt    time.Time
+	loadErr          error
+	loadedAt         time.Time
+	reloadPending    bool
+	diffTitle        string
+	viewing          string
+	overlay          types.Overlay
+	bannerErr        error
+	env              string
+	pendingEnv       string
+	filtering        bool
+	grouping         types.TablesGrouping
+	collapsedGroups  map[string]bool
+	listDescriptions bool
 }
 
 type modelConfig struct {
 	title  string
 	keymap help.KeyMap
+	// columnDefaults is set when the schema source reports column defaults, atlas inspection does not
+	columnDefaults bool
 }
 
 type viewModels struct {
-	help       help.Model
-	tablesList list.Model
-	colsChart  chart.Model
-	idxChart   chart.Model
-	fksChart   chart.Model
-	globe      spinner.Model
+	help        help.Model
+	tablesList  list.Model
+	colsChart   chart.Model
+	idxChart    chart.Model
+	fksChart    chart.Model
+	globe       spinner.Model
+	historyList list.Model
+	envsList    list.Model
+	details     map[types.TableDetailsSection]*detailRows
+	chartFilter textinput.Model
 }
 
 type model struct {
+	data                  *inspect.Data
+	diff                  inspect.Diff
 	schemasByName         map[string]inspect.Schema
 	tablesBySchemaAndName map[tableKey]inspect.Table
 
+	ctx                 context.Context
+	load                Loader
+	cancelLoad          context.CancelFunc
+	stderr              *logTail
+	history             *historyConfig
+	envs                *envsConfig
+	compare             *comparison
+	migrations          *migrationsConfig
+	plan                *migrationPlan
+	applyPlan           *applyPlan
+	migrationStatus     MigrationStatusLoader
+	migrationDir        *migrationDir
+	lintLoader          LintLoader
+	lint                *migrationLint
+	statsLoader         StatsLoader
+	stats               *tableStats
+	dataPreview         *dataPreviewConfig
+	tableData           *tableData
+	queryConfig         *queryConfig
+	query               *queryConsole
+	profileLoader       ProfileLoader
+	columnDefaultLoader ColumnDefaultLoader
+	compareSource       *compareSourceConfig
+	profile             *columnProfile
+	columnDetails       *columnDetails
+	coverage            *indexCoverage
+	redundant           *redundantIndexes
+	graph               *schemaGraph
+	impact              *columnImpact
+	chartViews          map[types.TableDetailsSection]*chartView
+
 	state  modelState
 	config modelConfig
 	vms    viewModels
@@ -63,42 +120,85 @@ type model struct {
 var _ tea.Model = &model{}
 
 func (m *model) Init() tea.Cmd {
-	return m.vms.globe.Tick
+	if m.envs != nil && m.state.env == "" {
+		m.openEnvs()
+		return m.vms.globe.Tick
+	}
+	if m.applyPlan != nil {
+		return tea.Batch(m.vms.globe.Tick, m.startLoad(), m.startApplyPlan())
+	}
+	return tea.Batch(m.vms.globe.Tick, m.startLoad())
 }
 
-func Run(ctx context.Context, title string, data inspect.Data) error {
-	m, err := newRootModel(title, data)
-	if err != nil {
-		return err
+func Run(ctx context.Context, title string, load Loader, opts ...Option) error {
+	var cfg runConfig
+	for _, opt := range opts {
+		opt(&cfg)
 	}
-	prog := tea.NewProgram(m)
+	m := newRootModel(ctx, title, load, cfg)
+	prog := tea.NewProgram(m, cfg.programOptions...)
 	go func() {
 		<-ctx.Done()
 		prog.Quit()
 	}()
+	if cfg.watchPath != "" {
+		done := make(chan struct{})
+		defer close(done)
+		if err := watch(prog, cfg.watchPath, done); err != nil {
+			return err
+		}
+	}
 	if _, err := prog.Run(); err != nil {
 		return err
 	}
 	return nil
 }
 
-func newRootModel(title string, data inspect.Data) (*model, error) {
+func newRootModel(ctx context.Context, title string, load Loader, cfg runConfig) *model {
 	m := &model{
 		schemasByName:         make(map[string]inspect.Schema),
 		tablesBySchemaAndName: make(map[tableKey]inspect.Table),
+		ctx:                   ctx,
+		load:                  load,
+		stderr:                newLogTail(maxStderrLines),
+		history:               cfg.history,
+		envs:                  cfg.envs,
+		migrations:            cfg.migrations,
+		migrationStatus:       cfg.migrationStatus,
+		lintLoader:            cfg.lint,
+		statsLoader:           cfg.stats,
+		dataPreview:           cfg.dataPreview,
+		queryConfig:           cfg.query,
+		profileLoader:         cfg.profile,
+		columnDefaultLoader:   cfg.columnDefault,
+		compareSource:         cfg.compareSource,
+		chartViews:            newChartViews(),
 		state: modelState{
-			selectedTab: types.ColumnsTable,
+			selectedTab:     types.ColumnsTable,
+			collapsedGroups: make(map[string]bool),
+			env:             cfg.env,
 		},
 		config: modelConfig{
-			keymap: keymap.GetKeyMap(),
-			title:  title,
+			keymap:         keymap.GetKeyMap(),
+			title:          title,
+			columnDefaults: cfg.columnDefaults,
 		},
 		vms: viewModels{
 			help:  help.New(),
 			globe: spinner.New(spinner.WithSpinner(spinner.Globe)),
 		},
 	}
+	if cfg.applyPlan != nil {
+		m.applyPlan = &applyPlan{load: cfg.applyPlan}
+	}
+	return m
+}
 
+// setData replaces the displayed data, keeping the current selections where they still exist
+func (m *model) setData(data inspect.Data) {
+	m.data = &data
+	m.schemasByName = make(map[string]inspect.Schema)
+	m.tablesBySchemaAndName = make(map[tableKey]inspect.Table)
 	for _, schema := range data.Schemas {
 		m.schemasByName[schema.Name] = schema
 		for _, table := range schema.Tables {
@@ -106,75 +206,149 @@ func newRootModel(title string, data inspect.Data) (*model, error) {
 		}
 	}
 
+	prevSchema := m.state.selectedSchema
+	prevTable := m.state.selectedTable
+	prevTab := m.state.selectedTab
+	prevTableIndex := m.vms.tablesList.Index()
+	prevCursors := []int{m.vms.colsChart.Cursor(), m.vms.idxChart.Cursor(), m.vms.fksChart.Cursor()}
+
 	// TODO - support multiple schemas
 	schema := data.Schemas[0]
+	if _, ok := m.schemasByName[prevSchema]; ok {
+		schema = m.schemasByName[prevSchema]
+	}
 	m.onSchemaSelected(schema.Name)
-	m.onTableSelected(tableKey{schema.Name, schema.Tables[0].Name})
+	if len(schema.Tables) == 0 {
+		return
+	}
+
+	tableIndex := lo.IndexOf(lo.Map(schema.Tables, func(table inspect.Table, _ int) string {
+		return table.Name
+	}), prevTable)
+	sameTable := tableIndex >= 0 && schema.Name == prevSchema
+	if tableIndex < 0 {
+		tableIndex = lo.Clamp(prevTableIndex, 0, len(schema.Tables)-1)
+	}
+	m.onTableSelected(tableKey{schema.Name, schema.Tables[tableIndex].Name})
+	m.selectTableItem(schema.Name, schema.Tables[tableIndex].Name)
+	if sameTable {
+		m.state.selectedTab = prevTab
+		m.vms.colsChart.SetCursor(prevCursors[0])
+		m.vms.idxChart.SetCursor(prevCursors[1])
+		m.vms.fksChart.SetCursor(prevCursors[2])
+	}
+}
+
+// selectTable shows the given table in the main view, returns false when it does not exist
+func (m *model) selectTable(schema, table string) bool {
+	if _, ok := m.tablesBySchemaAndName[tableKey{schema, table}]; !ok {
+		return false
+	}
+	switch {
+	case schema == m.state.selectedSchema:
+	case m.state.grouping == types.SchemaGrouping:
+		// every schema is listed already
+		m.state.selectedSchema = schema
+	default:
+		m.onSchemaSelected(schema)
+	}
+	m.onTableSelected(tableKey{schema, table})
+	m.selectTableItem(schema, table)
+	return true
+}
 
-	return m, nil
+func (m *model) loaded() bool {
+	return m.data != nil
 }
 
 func (m *model) onSchemaSelected(schema string) {
 	m.state.selectedSchema = schema
-	m.vms.tablesList = newTablesList(lo.Map(m.schemasByName[m.state.selectedSchema].Tables, func(chart inspect.Table, _ int) string {
-		return chart.Name
-	}))
 	m.state.selectedTable = ""
+	m.rebuildTablesList()
 }
 
 func (m *model) onTableSelected(key tableKey) {
 	m.state.selectedTable = key.tableName
 	m.state.selectedTab = types.ColumnsTable
-	m.vms.colsChart, m.vms.idxChart, m.vms.fksChart = newCharts(m.tablesBySchemaAndName[key])
+	m.vms.colsChart, m.vms.idxChart, m.vms.fksChart = newCharts(m.tablesBySchemaAndName[key], m.diff.Table(key.schemaName, key.tableName))
+	m.setDetailRows(m.tablesBySchemaAndName[key])
 }
 
-func newCharts(t inspect.Table) (colsChart chart.Model, idxChart chart.Model, fksChart chart.Model) {
-	colsChart = newChart(
-		[]chart.Column{
-			{Title: "Name", Width: 3},
-			{Title: "Type", Width: 2},
-			{Title: "Null", Width: 1},
-		},
-		lo.Map(t.Columns, func(col inspect.Column, _ int) chart.Row {
-			return chart.Row{
-				format.ColumnName(t, col),
-				col.Type,
-				format.Bool(col.Null),
-			}
-		}))
-
-	idxChart = newChart(
-		[]chart.Column{
-			{Title: "Name", Width: 5},
-			{Title: "Unique", Width: 1},
-			{Title: "Parts", Width: 3},
-		},
-		lo.Map(t.Indexes, func(idx inspect.Index, _ int) chart.Row {
-			return chart.Row{
-				idx.Name,
-				format.Bool(idx.Unique),
-				strings.Join(lo.Map(idx.Parts, func(part inspect.IndexPart, _ int) string {
-					return part.Column
-				}), format.InlineListSeparator),
-			}
-		}))
-
-	fksChart = newChart(
-		[]chart.Column{
-			{Title: "Name", Width: 2},
-			{Title: "Columns", Width: 1},
-			{Title: "References", Width: 1},
-		},
-		lo.Map(t.ForeignKeys, func(fk inspect.ForeignKey, _ int) chart.Row {
-			return chart.Row{
-				fk.Name,
-				strings.Join(fk.Columns, format.InlineListSeparator),
-				fmt.Sprintf("%s(%s)", fk.References.Table, strings.Join(fk.References.Columns, format.InlineListSeparator)),
-			}
-		}))
+// changeMarker prefixes items only once there is something to highlight, to keep alignment
+func (m *model) changeMarker(change inspect.Change) string {
+	if m.diff.Empty() {
+		return ""
+	}
+	return format.ChangeMarker(change)
+}
+
+var (
+	colsChartColumns = []chart.Column{
+		{Title: "Name", Width: 3},
+		{Title: "Type", Width: 2},
+		{Title: "Null", Width: 1},
+		{Title: "Index", Width: 2},
+	}
+	idxChartColumns = []chart.Column{
+		{Title: "Name", Width: 5},
+		{Title: "Unique", Width: 1},
+		{Title: "Parts", Width: 3},
+	}
+	fksChartColumns = []chart.Column{
+		{Title: "Name", Width: 2},
+		{Title: "Columns", Width: 1},
+		{Title: "References", Width: 1},
+	}
+)
+
+func newCharts(t inspect.Table, diff inspect.TableDiff) (colsChart chart.Model, idxChart chart.Model, fksChart chart.Model) {
+	marked := func(row chart.Row, changes map[string]inspect.Change, name string) chart.Row {
+		if diff.Change == inspect.Modified {
+			row[0] = format.ChangeMarker(changes[name]) + row[0]
+		}
+		return row
+	}
+
+	coverage := analyze.IndexCoverage(t)
+	colsChart = newChart(colsChartColumns, lo.Map(t.Columns, func(col inspect.Column, _ int) chart.Row {
+		return marked(columnRow(t, col, coverage[col.Name]), diff.Columns, col.Name)
+	}))
+	idxChart = newChart(idxChartColumns, lo.Map(t.Indexes, func(idx inspect.Index, _ int) chart.Row {
+		return marked(indexRow(idx), diff.Indexes, idx.Name)
+	}))
+	fksChart = newChart(fksChartColumns, lo.Map(t.ForeignKeys, func(fk inspect.ForeignKey, _ int) chart.Row {
+		return marked(foreignKeyRow(fk), diff.ForeignKeys, fk.Name)
+	}))
 	return
 }
 
+func columnRow(t inspect.Table, col inspect.Column, coverage analyze.Coverage) chart.Row {
+	return chart.Row{
+		format.ColumnName(t, col),
+		col.Type,
+		format.Bool(col.Null),
+		format.Coverage(coverage),
+	}
+}
+
+func indexRow(idx inspect.Index) chart.Row {
+	return chart.Row{
+		idx.Name,
+		format.Bool(idx.Unique),
+		strings.Join(lo.Map(idx.Parts, func(part inspect.IndexPart, _ int) string {
+			return part.Column
+		}), format.InlineListSeparator),
+	}
+}
+
+func foreignKeyRow(fk inspect.ForeignKey) chart.Row {
+	return chart.Row{
+		fk.Name,
+		strings.Join(fk.Columns, format.InlineListSeparator),
+		fmt.Sprintf("%s(%s)", fk.References.Table, strings.Join(fk.References.Columns, format.InlineListSeparator)),
+	}
+}
+
 func newChart(cols []chart.Column, rows []chart.Row) chart.Model {
 	return chart.New(
 		chart.WithColumns(cols),
@@ -184,16 +358,20 @@ func newChart(cols []chart.Column, rows []chart.Row) chart.Model {
 	)
 }
 
-func newTablesList(names []string) list.Model {
+func tablesListDelegate(showDescription bool) list.DefaultDelegate {
 	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = false
-	delegate.SetHeight(1)
+	delegate.ShowDescription = showDescription
+	delegate.SetHeight(lo.Ternary(showDescription, 2, 1))
 	delegate.SetSpacing(0)
+	return delegate
+}
+
+func newTablesList(items []types.TablesListItem) list.Model {
 	lst := list.New(
-		lo.Map(names, func(name string, _ int) list.Item {
-			return types.TablesListItem(name)
+		lo.Map(items, func(item types.TablesListItem, _ int) list.Item {
+			return item
 		}),
-		delegate,
+		tablesListDelegate(false),
 		0,
 		0,
 	)
@@ -0,0 +1,31 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+type Option func(*runConfig)
+
+type runConfig struct {
+	programOptions  []tea.ProgramOption
+	watchPath       string
+	history         *historyConfig
+	envs            *envsConfig
+	env             string
+	migrations      *migrationsConfig
+	applyPlan       ApplyPlanLoader
+	migrationStatus MigrationStatusLoader
+	lint            LintLoader
+	stats           StatsLoader
+	dataPreview     *dataPreviewConfig
+	query           *queryConfig
+	profile         ProfileLoader
+	columnDefaults  bool
+	columnDefault   ColumnDefaultLoader
+	compareSource   *compareSourceConfig
+}
+
+// WithInputTTY reads keystrokes from the terminal rather than stdin, for when stdin is used for data
+func WithInputTTY() Option {
+	return func(c *runConfig) {
+		c.programOptions = append(c.programOptions, tea.WithInputTTY())
+	}
+}
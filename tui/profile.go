@@ -0,0 +1,212 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/db"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/format"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"strings"
+	"time"
+)
+
+const (
+	// topValues is the number of most frequent values in a profile
+	topValues = 5
+	barWidth  = 20
+)
+
+// ProfileLoader profiles the values of a column of the env
+type ProfileLoader func(ctx context.Context, env, schema, table, column string, topN int) (*db.Profile, error)
+
+type columnKey struct {
+	tableKey
+	columnName string
+}
+
+// columnProfile is the profile of a column, kept until another column is profiled
+type columnProfile struct {
+	key       columnKey
+	colType   string
+	profile   *db.Profile
+	err       error
+	cancel    context.CancelFunc
+	startedAt time.Time
+}
+
+type profileLoadedMsg struct {
+	target  *columnProfile
+	profile *db.Profile
+	err     error
+}
+
+// WithColumnProfile profiles the values of the selected column on demand
+func WithColumnProfile(load ProfileLoader) Option {
+	return func(c *runConfig) {
+		c.profile = load
+	}
+}
+
+// selectedColumn is the column under the cursor of the columns chart
+func (m *model) selectedColumn() (inspect.Column, bool) {
+	table, ok := m.tablesBySchemaAndName[tableKey{m.state.selectedSchema, m.state.selectedTable}]
+	if !ok {
+		return inspect.Column{}, false
+	}
+	idx, ok := m.shownIndex(types.ColumnsTable, m.vms.colsChart.Cursor())
+	if !ok {
+		return inspect.Column{}, false
+	}
+	return table.Columns[idx], true
+}
+
+func (m *model) openProfile() tea.Cmd {
+	if m.profileLoader == nil {
+		m.state.bannerErr = fmt.Errorf("profiling columns requires a live database, set --url or --env")
+		return nil
+	}
+	col, ok := m.selectedColumn()
+	if m.state.selectedTab != types.ColumnsTable || !ok {
+		m.state.bannerErr = fmt.Errorf("select a column to profile in the %s tab", types.ColumnsTable.Title())
+		return nil
+	}
+	m.state.overlay = types.ProfileOverlay
+	key := columnKey{tableKey{m.state.selectedSchema, m.state.selectedTable}, col.Name}
+	if p := m.profile; p != nil && p.key == key {
+		return nil
+	}
+	return m.startProfile(key, col.Type)
+}
+
+func (m *model) startProfile(key columnKey, colType string) tea.Cmd {
+	if prev := m.profile; prev != nil && prev.cancel != nil {
+		prev.cancel()
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	p := &columnProfile{key: key, colType: colType, cancel: cancel, startedAt: time.Now()}
+	m.profile = p
+	load, env := m.profileLoader, m.state.env
+	return func() tea.Msg {
+		defer cancel()
+		profile, err := load(ctx, env, key.schemaName, key.tableName, key.columnName, topValues)
+		if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("cancelled after %s", formatElapsed(time.Since(p.startedAt)))
+		}
+		return profileLoadedMsg{target: p, profile: profile, err: err}
+	}
+}
+
+func (m *model) onProfileLoaded(msg profileLoadedMsg) {
+	p := m.profile
+	if p == nil || p != msg.target {
+		return
+	}
+	p.cancel = nil
+	p.profile = msg.profile
+	p.err = msg.err
+}
+
+func (m *model) updateProfile(msg tea.KeyMsg) tea.Cmd {
+	p := m.profile
+	switch {
+	case key.Matches(msg, keymap.Cancel) && p.cancel != nil:
+		p.cancel()
+	case key.Matches(msg, keymap.Back):
+		m.state.overlay = types.NoOverlay
+	case key.Matches(msg, keymap.Quit):
+		m.state.quitting = true
+		return tea.Quit
+	case key.Matches(msg, keymap.Refresh) && p.cancel == nil:
+		return m.startProfile(p.key, p.colType)
+	}
+	return nil
+}
+
+func (m *model) profileView(width, height int) string {
+	p := m.profile
+	title := styles.TitleStyle.Render(fmt.Sprintf("Profile of %s.%s", p.key.tableName, p.key.columnName))
+	switch {
+	case p.err != nil:
+		return withBorder(centeredBox(width, height,
+			styles.ErrorStyle.Render(fmt.Sprintf("Failed to profile %s", p.key.columnName)), "", styles.ErrorStyle.Render(p.err.Error()),
+		), true)
+	case p.profile == nil:
+		return withBorder(centeredBox(width, height,
+			fmt.Sprintf("%s Profiling %s... %s (%s to cancel)", m.vms.globe.View(), p.key.columnName, formatElapsed(time.Since(p.startedAt)), keymap.Cancel.Help().Key),
+		), true)
+	}
+
+	pr := p.profile
+	rows := fmt.Sprintf("%s rows", format.Count(int64(pr.Rows)))
+	switch {
+	case pr.Sampling == db.RandomRows:
+		rows = fmt.Sprintf("sampled %s of ~%s rows at random", format.Count(int64(pr.Rows)), format.Count(pr.TableRows))
+	case pr.Sampling == db.FirstRows && pr.TableRows == db.UnknownRows:
+		rows = fmt.Sprintf("first %s rows only, the table has more", format.Count(int64(pr.Rows)))
+	case pr.Sampling == db.FirstRows:
+		rows = fmt.Sprintf("first %s of ~%s rows only, the table cannot be sampled at random", format.Count(int64(pr.Rows)), format.Count(pr.TableRows))
+	}
+	lines := []string{
+		title,
+		styles.SubTitleStyle.Render(fmt.Sprintf("%s · %s values · %s", p.colType, pr.Kind, rows)),
+		"",
+		fmt.Sprintf("nulls     %s (%.1f%%)", format.Count(int64(pr.Nulls)), pr.NullRatio()*100),
+		fmt.Sprintf("distinct  %s", format.Count(int64(pr.Distinct))),
+	}
+	if pr.Distinct > 0 {
+		lines = append(lines,
+			fmt.Sprintf("min       %s", dataCell(&pr.Min)),
+			fmt.Sprintf("max       %s", dataCell(&pr.Max)),
+		)
+	}
+	var frequent, distribution []string
+	switch {
+	case len(pr.Top) > 0 && pr.Distinct == pr.Rows-pr.Nulls:
+		frequent = []string{styles.TitleStyle.Render("Most frequent"), "all values are distinct"}
+	case len(pr.Top) > 0:
+		labels := lo.Map(pr.Top, func(v db.ValueCount, _ int) string { return dataCell(&v.Value) })
+		frequent = append([]string{styles.TitleStyle.Render("Most frequent")},
+			bars(labels, lo.Map(pr.Top, func(v db.ValueCount, _ int) int { return v.Count }), pr.Rows)...)
+	}
+	if len(pr.Histogram) > 0 {
+		labels := lo.Map(pr.Histogram, func(b db.Bucket, _ int) string { return b.From })
+		distribution = append([]string{styles.TitleStyle.Render("Distribution")},
+			bars(labels, lo.Map(pr.Histogram, func(b db.Bucket, _ int) int { return b.Count }), pr.Rows)...)
+	}
+	lines = append(lines, "", lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.JoinVertical(lipgloss.Left, frequent...), "    ", lipgloss.JoinVertical(lipgloss.Left, distribution...),
+	))
+	help := styles.SubTitleStyle.Render(fmt.Sprintf("%s re-profile · %s close", keymap.Refresh.Help().Key, keymap.Back.Help().Key))
+
+	body := lipgloss.NewStyle().Height(height - lipgloss.Height(help)).MaxHeight(height - lipgloss.Height(help)).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, body, help),
+	), true)
+}
+
+// bars renders a horizontal bar chart of the counts, scaled to the largest of them
+func bars(labels []string, counts []int, total int) []string {
+	labelWidth := lo.Max(lo.Map(labels, func(label string, _ int) int { return lipgloss.Width(label) }))
+	highest := lo.Max(counts)
+	return lo.Map(labels, func(label string, i int) string {
+		width := 0
+		if highest > 0 {
+			width = counts[i] * barWidth / highest
+		}
+		bar := strings.Repeat("█", width) + strings.Repeat(" ", barWidth-width)
+		share := 0.0
+		if total > 0 {
+			share = float64(counts[i]) / float64(total) * 100
+		}
+		return fmt.Sprintf("%-*s %s %s (%.1f%%)", labelWidth, label, styles.TitleStyle.Render(bar), format.Count(int64(counts[i])), share)
+	})
+}
@@ -0,0 +1,302 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	chart "github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/db"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxQueryRows caps the rows read by a query, the console is for a look rather than for exports
+const maxQueryRows = 500
+
+// QueryRunner runs a read-only query on the database of the env, reading up to limit rows
+type QueryRunner func(ctx context.Context, env, query string, limit int) (*db.Page, error)
+
+// SourceNamer names the source inspected for the env, e.g. its redacted url, or empty for offline inputs
+type SourceNamer func(env string) string
+
+type queryConfig struct {
+	run     QueryRunner
+	history *db.QueryHistory
+	source  SourceNamer
+}
+
+// queryConsole is kept while closed, so reopening it shows the last query and its results
+type queryConsole struct {
+	input      textinput.Model
+	history    []string
+	historyIdx int
+	draft      string
+	completion *completion
+	running    bool
+	cancel     context.CancelFunc
+	startedAt  time.Time
+	took       time.Duration
+	query      string
+	result     *db.Page
+	err        error
+	chart      chart.Model
+}
+
+// completion cycles through the candidates completing the word before the cursor
+type completion struct {
+	start      int
+	candidates []string
+	idx        int
+}
+
+type queryDoneMsg struct {
+	target  *queryConsole
+	page    *db.Page
+	took    time.Duration
+	err     error
+	saveErr error
+}
+
+// WithQueryConsole adds a read-only sql console, keeping the queries of each source in the history
+func WithQueryConsole(run QueryRunner, history *db.QueryHistory, source SourceNamer) Option {
+	return func(c *runConfig) {
+		c.query = &queryConfig{run: run, history: history, source: source}
+	}
+}
+
+func (m *model) openQuery() tea.Cmd {
+	if m.queryConfig == nil {
+		m.state.bannerErr = fmt.Errorf("the query console requires a live database, set --url or --env")
+		return nil
+	}
+	if m.query == nil {
+		input := textinput.New()
+		input.Prompt = "> "
+		input.Placeholder = "SELECT * FROM ..."
+		m.query = &queryConsole{input: input}
+	}
+	history, err := m.queryConfig.history.List(m.queryConfig.source(m.state.env))
+	if err != nil {
+		m.state.bannerErr = err
+	}
+	m.query.history = history
+	m.query.historyIdx = -1
+	m.state.overlay = types.QueryOverlay
+	return m.query.input.Focus()
+}
+
+func (m *model) runQuery() tea.Cmd {
+	q := m.query
+	query := strings.TrimSpace(q.input.Value())
+	if query == "" || q.running {
+		return nil
+	}
+	if err := db.CheckReadOnly(query); err != nil {
+		q.err = err
+		return nil
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	q.running = true
+	q.cancel = cancel
+	q.startedAt = time.Now()
+	q.query = query
+	cfg, env := m.queryConfig, m.state.env
+	source := cfg.source(env)
+	return func() tea.Msg {
+		defer cancel()
+		page, err := cfg.run(ctx, env, query, maxQueryRows)
+		took := time.Since(q.startedAt)
+		if errors.Is(err, context.Canceled) {
+			return queryDoneMsg{target: q, err: fmt.Errorf("cancelled after %s", formatElapsed(took))}
+		}
+		if err != nil {
+			return queryDoneMsg{target: q, err: err}
+		}
+		return queryDoneMsg{target: q, page: page, took: took, saveErr: cfg.history.Add(source, query)}
+	}
+}
+
+func (m *model) onQueryDone(msg queryDoneMsg) {
+	q := m.query
+	if q == nil || q != msg.target {
+		return
+	}
+	q.running = false
+	q.cancel = nil
+	q.err = msg.err
+	if msg.saveErr != nil {
+		m.state.bannerErr = msg.saveErr
+	}
+	if msg.err != nil {
+		return
+	}
+	q.result = msg.page
+	q.took = msg.took
+	q.history = append([]string{q.query}, lo.Without(q.history, q.query)...)
+	q.historyIdx = -1
+	// flex widths account for cell padding only when they sum to twice the columns count
+	q.chart = newChart(
+		lo.Map(q.result.Columns, func(column string, _ int) chart.Column { return chart.Column{Title: column, Width: 2} }),
+		lo.Map(q.result.Rows, func(row []*string, _ int) chart.Row {
+			return lo.Map(row, func(value *string, _ int) string { return dataCell(value) })
+		}),
+	)
+}
+
+func (m *model) updateQuery(msg tea.KeyMsg) tea.Cmd {
+	q := m.query
+	if msg.Type != tea.KeyTab {
+		q.completion = nil
+	}
+	// plain keys are typed into the query, so bindings of letters do not apply here
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state.overlay = types.NoOverlay
+		q.input.Blur()
+	case tea.KeyCtrlC:
+		if q.cancel != nil {
+			q.cancel()
+		}
+	case tea.KeyEnter:
+		return m.runQuery()
+	case tea.KeyTab:
+		m.completeQuery()
+	case tea.KeyUp, tea.KeyDown:
+		m.browseQueryHistory(msg.Type == tea.KeyUp)
+	case tea.KeyPgUp, tea.KeyPgDown:
+		var cmd tea.Cmd
+		q.chart, cmd = q.chart.Update(msg)
+		return cmd
+	default:
+		var cmd tea.Cmd
+		q.input, cmd = q.input.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *model) browseQueryHistory(older bool) {
+	q := m.query
+	idx := lo.Clamp(q.historyIdx+lo.Ternary(older, 1, -1), -1, len(q.history)-1)
+	if idx == q.historyIdx {
+		return
+	}
+	if q.historyIdx == -1 {
+		q.draft = q.input.Value()
+	}
+	q.historyIdx = idx
+	if idx == -1 {
+		q.input.SetValue(q.draft)
+	} else {
+		q.input.SetValue(q.history[idx])
+	}
+	q.input.CursorEnd()
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// completeQuery completes table and column names, cycling through the candidates on repeated completions
+func (m *model) completeQuery() {
+	q := m.query
+	value := []rune(q.input.Value())
+	pos := q.input.Position()
+	if q.completion == nil {
+		start := pos
+		for start > 0 && (isIdentRune(value[start-1]) || value[start-1] == '.') {
+			start--
+		}
+		word := string(value[start:pos])
+		qualifier := ""
+		if dot := strings.LastIndex(word, "."); dot >= 0 {
+			qualifier, word = word[:dot], word[dot+1:]
+			start += len([]rune(qualifier)) + 1
+		}
+		candidates := lo.Filter(m.completionCandidates(qualifier, string(value)), func(candidate string, _ int) bool {
+			return strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(word)) && !strings.EqualFold(candidate, word)
+		})
+		if len(candidates) == 0 {
+			return
+		}
+		q.completion = &completion{start: start, candidates: candidates, idx: -1}
+	}
+	c := q.completion
+	c.idx = (c.idx + 1) % len(c.candidates)
+	completed := append(append(slices.Clone(value[:c.start]), []rune(c.candidates[c.idx])...), value[pos:]...)
+	q.input.SetValue(string(completed))
+	q.input.SetCursor(c.start + len([]rune(c.candidates[c.idx])))
+}
+
+// completionCandidates are the columns of the qualifying table, or the tables along with the columns of tables in the query
+func (m *model) completionCandidates(qualifier, query string) []string {
+	tables := m.schemasByName[m.state.selectedSchema].Tables
+	if qualifier != "" {
+		table, _ := lo.Find(tables, func(table inspect.Table) bool { return strings.EqualFold(table.Name, qualifier) })
+		return lo.Map(table.Columns, func(col inspect.Column, _ int) string { return col.Name })
+	}
+	words := lo.SliceToMap(strings.FieldsFunc(strings.ToLower(query), func(r rune) bool { return !isIdentRune(r) }), func(word string) (string, bool) {
+		return word, true
+	})
+	candidates := lo.Map(tables, func(table inspect.Table, _ int) string { return table.Name })
+	for _, table := range tables {
+		if words[strings.ToLower(table.Name)] {
+			candidates = append(candidates, lo.Map(table.Columns, func(col inspect.Column, _ int) string { return col.Name })...)
+		}
+	}
+	return lo.Uniq(candidates)
+}
+
+func (m *model) queryView(width, height int) string {
+	q := m.query
+	header := lipgloss.JoinVertical(lipgloss.Left,
+		styles.TitleStyle.Render("Query console (read-only)"),
+		styles.SubTitleStyle.Render(m.queryConfig.source(m.state.env)),
+	)
+	q.input.Width = width - 4
+	input := withBorder(lipgloss.NewStyle().Width(width-2).Render(q.input.View()), true)
+
+	var status string
+	switch {
+	case q.completion != nil:
+		candidates := lo.Map(q.completion.candidates, func(candidate string, i int) string {
+			return lo.Ternary(i == q.completion.idx, styles.TitleStyle.Render(candidate), candidate)
+		})
+		status = styles.SubTitleStyle.Render("complete: ") + strings.Join(candidates, styles.SubTitleStyle.Render(" · "))
+	case q.running:
+		status = fmt.Sprintf("%s running... %s (%s to cancel)", m.vms.globe.View(), formatElapsed(time.Since(q.startedAt)), keymap.Cancel.Help().Key)
+	case q.err != nil:
+		status = styles.ErrorStyle.Render(firstLine(q.err.Error()))
+	case q.result != nil:
+		rows := lo.Ternary(q.result.HasMore, fmt.Sprintf("first %d rows", len(q.result.Rows)), fmt.Sprintf("%d rows", len(q.result.Rows)))
+		status = styles.SubTitleStyle.Render(fmt.Sprintf("%s in %s", rows, q.took.Round(time.Millisecond)))
+	default:
+		status = styles.SubTitleStyle.Render(fmt.Sprintf("%d queries in history", len(q.history)))
+	}
+	status = lipgloss.NewStyle().MaxWidth(width).Render(status)
+	help := styles.SubTitleStyle.Render("enter run · tab complete · ↑/↓ history · pgup/pgdown scroll results · esc close")
+
+	resultsHeight := height - lipgloss.Height(header) - lipgloss.Height(input) - lipgloss.Height(status) - lipgloss.Height(help)
+	var results string
+	if q.result != nil && len(q.result.Rows) > 0 {
+		q.chart.SetWidth(width)
+		// the chart renders its header on top of its height
+		q.chart.SetHeight(resultsHeight - 1)
+		results = q.chart.View()
+	}
+	results = lipgloss.NewStyle().Height(resultsHeight).MaxHeight(resultsHeight).Render(results)
+
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, header, input, status, results, help),
+	), true)
+}
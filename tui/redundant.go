@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/reallyliri/atlastui/analyze"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+)
+
+// redundantIndexes lists the indexes of the selected schema that other indexes make redundant
+type redundantIndexes struct {
+	schemaName string
+	redundant  linkList
+}
+
+func (m *model) openRedundantIndexes() {
+	schema := m.schemasByName[m.state.selectedSchema]
+	redundant := analyze.RedundantIndexes(schema)
+	m.redundant = &redundantIndexes{
+		schemaName: schema.Name,
+		redundant: linkList{
+			items: lo.Map(redundant, func(redundant analyze.RedundantIndex, _ int) linkItem {
+				return linkItem{section: redundant.Table, label: fmt.Sprintf("%s · %s", redundant.Index, redundant.Reason())}
+			}),
+			empty:  "no redundant indexes",
+			action: "go to index",
+			open: func(item int) bool {
+				key := tableKey{schema.Name, redundant[item].Table}
+				indexes := lo.Map(m.tablesBySchemaAndName[key].Indexes, func(idx inspect.Index, _ int) string { return idx.Name })
+				return m.goToLink(columnLink{table: key, tab: types.IndexesTable, row: lo.IndexOf(indexes, redundant[item].Index)})
+			},
+		},
+	}
+	m.state.overlay = types.RedundantIndexesOverlay
+}
+
+func (m *model) redundantIndexesView(width, height int) string {
+	r := m.redundant
+	return r.redundant.view([]string{
+		styles.TitleStyle.Render(fmt.Sprintf("Redundant indexes of %s", r.schemaName)),
+		styles.SubTitleStyle.Render("duplicates and prefixes of other indexes, unique indexes are only covered by their unique duplicates"),
+	}, width, height)
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	chart "github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/stats"
+	"github.com/reallyliri/atlastui/tui/format"
+	"github.com/reallyliri/atlastui/tui/keymap"
+	"github.com/reallyliri/atlastui/tui/styles"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"slices"
+	"strings"
+)
+
+// StatsLoader fetches the statistics of the tables of the given schemas of the env
+type StatsLoader func(ctx context.Context, env string, schemas []string) ([]stats.Table, error)
+
+// tableStats are the statistics of the displayed tables, fetched after each inspection
+type tableStats struct {
+	tables  map[tableKey]stats.Table
+	loaded  bool
+	err     error
+	sortBy  int
+	reverse bool
+	chart   chart.Model
+}
+
+type statsLoadedMsg struct {
+	target *tableStats
+	tables []stats.Table
+	err    error
+}
+
+// statsColumn is a column of the overview, along with how it sorts
+type statsColumn struct {
+	title   string
+	width   int
+	value   func(t stats.Table) string
+	compare func(a, b stats.Table) int
+}
+
+var statsColumns = []statsColumn{
+	{"Table", 4, func(t stats.Table) string { return t.Name }, func(a, b stats.Table) int { return cmp.Compare(a.Name, b.Name) }},
+	{"Rows", 2, statsRows, func(a, b stats.Table) int { return cmp.Compare(a.Rows, b.Rows) }},
+	{"Data", 2, func(t stats.Table) string { return format.Bytes(t.DataSize) }, func(a, b stats.Table) int { return cmp.Compare(a.DataSize, b.DataSize) }},
+	{"Indexes", 2, func(t stats.Table) string { return format.Bytes(t.IndexSize) }, func(a, b stats.Table) int { return cmp.Compare(a.IndexSize, b.IndexSize) }},
+	{"Total", 2, func(t stats.Table) string { return format.Bytes(t.TotalSize()) }, func(a, b stats.Table) int { return cmp.Compare(a.TotalSize(), b.TotalSize()) }},
+	{"Analyzed", 1, func(t stats.Table) string { return format.Date(t.LastAnalyze) }, func(a, b stats.Table) int { return a.LastAnalyze.Compare(b.LastAnalyze) }},
+	{"Vacuumed", 1, func(t stats.Table) string { return format.Date(t.LastVacuum) }, func(a, b stats.Table) int { return a.LastVacuum.Compare(b.LastVacuum) }},
+}
+
+// defaultStatsSort puts the heaviest tables first
+const defaultStatsSort = 4
+
+func statsRows(t stats.Table) string {
+	if t.Exact || t.Rows < 0 {
+		return format.Count(t.Rows)
+	}
+	return "~" + format.Count(t.Rows)
+}
+
+// WithStats fetches table statistics after each inspection, showing them in the table header and in an overview
+func WithStats(load StatsLoader) Option {
+	return func(c *runConfig) {
+		c.stats = load
+	}
+}
+
+func (m *model) startStats() tea.Cmd {
+	if m.statsLoader == nil || !m.loaded() {
+		return nil
+	}
+	s := &tableStats{sortBy: defaultStatsSort, reverse: true}
+	if prev := m.stats; prev != nil {
+		// keep showing the previous statistics until the new ones arrive
+		s.sortBy, s.reverse = prev.sortBy, prev.reverse
+		s.tables, s.loaded, s.err, s.chart = prev.tables, prev.loaded, prev.err, prev.chart
+	}
+	m.stats = s
+	load := m.statsLoader
+	ctx, env := m.ctx, m.state.env
+	schemas := lo.Map(m.data.Schemas, func(schema inspect.Schema, _ int) string { return schema.Name })
+	return func() tea.Msg {
+		tables, err := load(ctx, env, schemas)
+		return statsLoadedMsg{target: s, tables: tables, err: err}
+	}
+}
+
+func (m *model) onStatsLoaded(msg statsLoadedMsg) {
+	s := m.stats
+	if s == nil || s != msg.target {
+		return
+	}
+	s.loaded = true
+	s.err = msg.err
+	s.tables = lo.KeyBy(msg.tables, func(t stats.Table) tableKey { return tableKey{t.Schema, t.Name} })
+	m.sortStats()
+}
+
+// selectedTableStats describes the statistics of the selected table, for the table header
+func (m *model) selectedTableStats() string {
+	if m.stats == nil {
+		return ""
+	}
+	t, ok := m.stats.tables[tableKey{m.state.selectedSchema, m.state.selectedTable}]
+	if !ok {
+		return ""
+	}
+	parts := []string{fmt.Sprintf("%s rows", statsRows(t))}
+	if t.DataSize != stats.Unknown {
+		parts = append(parts, fmt.Sprintf("data %s", format.Bytes(t.DataSize)))
+	}
+	if t.IndexSize != stats.Unknown {
+		parts = append(parts, fmt.Sprintf("indexes %s", format.Bytes(t.IndexSize)))
+	}
+	if !t.LastAnalyze.IsZero() {
+		parts = append(parts, fmt.Sprintf("analyzed %s", format.Date(t.LastAnalyze)))
+	}
+	if !t.LastVacuum.IsZero() {
+		parts = append(parts, fmt.Sprintf("vacuumed %s", format.Date(t.LastVacuum)))
+	}
+	return strings.Join(parts, format.TabsSeparator)
+}
+
+func (m *model) openStats() {
+	if m.statsLoader == nil {
+		m.state.bannerErr = fmt.Errorf("no table statistics, run with --stats")
+		return
+	}
+	m.state.overlay = types.StatsOverlay
+}
+
+// sortStats rebuilds the overview chart of the tables of the selected schema
+func (m *model) sortStats() {
+	s := m.stats
+	tables := lo.Filter(lo.Values(s.tables), func(t stats.Table, _ int) bool { return t.Schema == m.state.selectedSchema })
+	column := statsColumns[s.sortBy]
+	slices.SortStableFunc(tables, func(a, b stats.Table) int {
+		return cmp.Or(lo.Ternary(s.reverse, -1, 1)*column.compare(a, b), cmp.Compare(a.Name, b.Name))
+	})
+	columns := lo.Map(statsColumns, func(c statsColumn, i int) chart.Column {
+		title := c.title
+		if i == s.sortBy {
+			title += lo.Ternary(s.reverse, " ▼", " ▲")
+		}
+		return chart.Column{Title: title, Width: c.width}
+	})
+	s.chart = newChart(columns, lo.Map(tables, func(t stats.Table, _ int) chart.Row {
+		return lo.Map(statsColumns, func(c statsColumn, _ int) string { return c.value(t) })
+	}))
+}
+
+func (m *model) updateStats(msg tea.KeyMsg) tea.Cmd {
+	s := m.stats
+	switch {
+	case key.Matches(msg, keymap.Back):
+		m.state.overlay = types.NoOverlay
+	case key.Matches(msg, keymap.Quit):
+		m.state.quitting = true
+		return tea.Quit
+	case key.Matches(msg, keymap.Refresh):
+		return m.startStats()
+	case s == nil || !s.loaded || s.err != nil:
+		// nothing to act on yet
+	case key.Matches(msg, keymap.Left):
+		s.sortBy = (s.sortBy + len(statsColumns) - 1) % len(statsColumns)
+		m.sortStats()
+	case key.Matches(msg, keymap.Right):
+		s.sortBy = (s.sortBy + 1) % len(statsColumns)
+		m.sortStats()
+	case key.Matches(msg, keymap.Toggle):
+		s.reverse = !s.reverse
+		m.sortStats()
+	case key.Matches(msg, keymap.Select):
+		row := s.chart.SelectedRow()
+		if row == nil {
+			return nil
+		}
+		if m.selectTable(m.state.selectedSchema, row[0]) {
+			m.state.overlay = types.NoOverlay
+		}
+	default:
+		var cmd tea.Cmd
+		s.chart, cmd = s.chart.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *model) statsView(width, height int) string {
+	s := m.stats
+	switch {
+	case s == nil || !s.loaded:
+		return withBorder(centeredBox(width, height,
+			fmt.Sprintf("%s Fetching table statistics...", m.vms.globe.View()),
+		), true)
+	case s.err != nil:
+		return withBorder(centeredBox(width, height,
+			styles.ErrorStyle.Render("Failed to fetch table statistics"), "", styles.ErrorStyle.Render(s.err.Error()),
+			styles.SubTitleStyle.Render(fmt.Sprintf("press %s to retry", keymap.Refresh.Help().Key)),
+		), true)
+	}
+
+	summary := fmt.Sprintf("%d tables", len(s.chart.Rows()))
+	if sizes := lo.Filter(lo.Values(s.tables), func(t stats.Table, _ int) bool { return t.TotalSize() != stats.Unknown }); len(sizes) > 0 {
+		summary += fmt.Sprintf(" · %s in total", format.Bytes(lo.SumBy(sizes, stats.Table.TotalSize)))
+	}
+	header := lipgloss.JoinVertical(lipgloss.Left,
+		styles.TitleStyle.Render(fmt.Sprintf("Table statistics of %s", m.state.selectedSchema)),
+		styles.SubTitleStyle.Render(summary),
+	)
+	help := styles.SubTitleStyle.Render(fmt.Sprintf(
+		"%s/%s sort column · %s reverse · %s show table · %s refresh · %s close",
+		keymap.Left.Help().Key, keymap.Right.Help().Key, keymap.Toggle.Help().Key, keymap.Select.Help().Key, keymap.Refresh.Help().Key, keymap.Back.Help().Key,
+	))
+	s.chart.SetWidth(width)
+	// the chart renders its header on top of its height
+	s.chart.SetHeight(height - lipgloss.Height(header) - lipgloss.Height(help) - 1)
+	return withBorder(lipgloss.NewStyle().Width(width).Height(height).Render(
+		lipgloss.JoinVertical(lipgloss.Left, header, s.chart.View(), help),
+	), true)
+}
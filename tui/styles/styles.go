@@ -9,6 +9,7 @@ const (
 	WhiteTint         = lipgloss.Color("#f0f0f0")
 	BlueTint          = lipgloss.Color("#388de9")
 	GreenTint         = lipgloss.Color("#46b17b")
+	RedTint           = lipgloss.Color("#e5534b")
 	BorderFocusedTint = lipgloss.Color("63")
 	BorderBluredTint  = lipgloss.Color("240")
 )
@@ -23,4 +24,8 @@ var (
 	BorderFocusedStyle      = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(BorderFocusedTint)
 	BorderBluredStyle       = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(BorderBluredTint)
 	NoDataStyle             = lipgloss.NewStyle().Foreground(SubTitleTint).AlignHorizontal(lipgloss.Center).Padding(2)
+	ErrorStyle              = lipgloss.NewStyle().Foreground(RedTint)
+	SQLKeywordStyle         = lipgloss.NewStyle().Foreground(BlueTint)
+	SQLLiteralStyle         = lipgloss.NewStyle().Foreground(GreenTint)
+	SQLCommentStyle         = SubTitleStyle
 )
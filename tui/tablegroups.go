@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"cmp"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/reallyliri/atlastui/inspect"
+	"github.com/reallyliri/atlastui/migrate"
+	"github.com/reallyliri/atlastui/tui/types"
+	"github.com/samber/lo"
+	"slices"
+	"strings"
+)
+
+// tableGroup is a group of tables, shown as a collapsible node of the tables list
+type tableGroup struct {
+	name   string
+	tables []tableKey
+}
+
+// tableGroups groups the tables by the current grouping, tables left out of any group are listed after the groups
+func (m *model) tableGroups() (groups []tableGroup, ungrouped []tableKey) {
+	schema := m.schemasByName[m.state.selectedSchema]
+	keys := lo.Map(schema.Tables, func(t inspect.Table, _ int) tableKey { return tableKey{schema.Name, t.Name} })
+	switch m.state.grouping {
+	case types.PrefixGrouping:
+		byPrefix := lo.GroupBy(keys, func(key tableKey) string {
+			if i := strings.Index(key.tableName, "_"); i > 0 {
+				return key.tableName[:i+1]
+			}
+			return ""
+		})
+		for prefix, tables := range byPrefix {
+			if prefix != "" && len(tables) > 1 {
+				groups = append(groups, tableGroup{name: prefix, tables: tables})
+			}
+		}
+		slices.SortFunc(groups, func(a, b tableGroup) int { return cmp.Compare(a.name, b.name) })
+		grouped := lo.FlatMap(groups, func(g tableGroup, _ int) []tableKey { return g.tables })
+		ungrouped = lo.Without(keys, grouped...)
+	case types.SchemaGrouping:
+		groups = lo.Map(m.data.Schemas, func(s inspect.Schema, _ int) tableGroup {
+			return tableGroup{name: s.Name, tables: lo.Map(s.Tables, func(t inspect.Table, _ int) tableKey { return tableKey{s.Name, t.Name} })}
+		})
+	case types.RelationsGrouping:
+		for _, component := range schema.Components() {
+			tables := lo.Map(component, func(name string, _ int) tableKey { return tableKey{schema.Name, name} })
+			if len(tables) == 1 {
+				ungrouped = append(ungrouped, tables[0])
+				continue
+			}
+			groups = append(groups, tableGroup{name: hubTable(schema, component), tables: tables})
+		}
+	default:
+		ungrouped = keys
+	}
+	return
+}
+
+// hubTable names a group of related tables by the table most referenced within it
+func hubTable(schema inspect.Schema, tables []string) string {
+	inbound := make(map[string]int)
+	for _, t := range schema.Tables {
+		for _, fk := range t.ForeignKeys {
+			inbound[fk.References.Table]++
+		}
+	}
+	return lo.MaxBy(tables, func(a, b string) bool { return inbound[a] > inbound[b] })
+}
+
+func (m *model) tablesListItems() []types.TablesListItem {
+	groups, ungrouped := m.tableGroups()
+	var items []types.TablesListItem
+	for _, g := range groups {
+		collapsed := m.state.collapsedGroups[g.name]
+		items = append(items, types.TablesListItem{Group: g.name, Tables: len(g.tables), Collapsed: collapsed})
+		if !collapsed {
+			items = append(items, lo.Map(g.tables, func(key tableKey, _ int) types.TablesListItem { return m.tableItem(key, true) })...)
+		}
+	}
+	return append(items, lo.Map(ungrouped, func(key tableKey, _ int) types.TablesListItem { return m.tableItem(key, false) })...)
+}
+
+func (m *model) tableItem(key tableKey, grouped bool) types.TablesListItem {
+	t := m.tablesBySchemaAndName[key]
+	return types.TablesListItem{
+		Name:         key.tableName,
+		Schema:       key.schemaName,
+		Marker:       m.changeMarker(m.diff.Table(key.schemaName, key.tableName).Change),
+		Grouped:      grouped,
+		Columns:      len(t.Columns),
+		Indexes:      len(t.Indexes),
+		ForeignKeys:  len(t.ForeignKeys),
+		NoPrimaryKey: t.PrimaryKey == nil,
+		Comment:      t.Comment,
+		Warnings:     m.lintWarnings(key),
+	}
+}
+
+// lintWarnings counts the diagnostics of the last lint run on the table, diagnostics without a schema are of the selected one
+func (m *model) lintWarnings(key tableKey) int {
+	if m.lint == nil || m.lint.lint == nil {
+		return 0
+	}
+	return lo.CountBy(m.lint.lint.Diagnostics, func(d migrate.Diagnostic) bool {
+		return d.Table == key.tableName && cmp.Or(d.Schema, m.state.selectedSchema) == key.schemaName
+	})
+}
+
+// rebuildTablesList lists the tables by the current grouping, keeping the selected table in sight
+func (m *model) rebuildTablesList() {
+	m.vms.tablesList = newTablesList(m.tablesListItems())
+	if m.state.listDescriptions {
+		m.vms.tablesList.SetDelegate(tablesListDelegate(true))
+	}
+	if m.state.grouping != types.NoGrouping {
+		m.vms.tablesList.SetShowStatusBar(false)
+		m.vms.tablesList.SetShowTitle(true)
+		m.vms.tablesList.Title = m.state.grouping.Title()
+	}
+	m.selectTableItem(m.state.selectedSchema, m.state.selectedTable)
+}
+
+// selectTableItem moves the cursor of the tables list to the given table, expanding its group when collapsed
+func (m *model) selectTableItem(schema, table string) {
+	if table == "" {
+		return
+	}
+	idx := m.tableItemIndex(schema, table)
+	if idx < 0 {
+		groups, _ := m.tableGroups()
+		g, ok := lo.Find(groups, func(g tableGroup) bool { return lo.Contains(g.tables, tableKey{schema, table}) })
+		if !ok || !m.state.collapsedGroups[g.name] {
+			return
+		}
+		delete(m.state.collapsedGroups, g.name)
+		m.refreshTablesList()
+		idx = m.tableItemIndex(schema, table)
+	}
+	m.vms.tablesList.Select(idx)
+}
+
+// refreshTablesList updates the items of the tables list in place, e.g. once their badges change
+func (m *model) refreshTablesList() {
+	m.vms.tablesList.SetItems(lo.Map(m.tablesListItems(), func(item types.TablesListItem, _ int) list.Item { return item }))
+}
+
+// toggleListDescriptions shows the counts and comments of the tables below their names
+func (m *model) toggleListDescriptions() {
+	m.state.listDescriptions = !m.state.listDescriptions
+	m.vms.tablesList.SetDelegate(tablesListDelegate(m.state.listDescriptions))
+}
+
+func (m *model) tableItemIndex(schema, table string) int {
+	_, idx, _ := lo.FindIndexOf(m.vms.tablesList.Items(), func(item list.Item) bool {
+		t := item.(types.TablesListItem)
+		return !t.IsGroup() && t.Schema == schema && t.Name == table
+	})
+	return idx
+}
+
+func (m *model) cycleGrouping() {
+	m.state.grouping = (m.state.grouping + 1) % types.TablesGrouping(len(types.TablesGroupings))
+	m.state.collapsedGroups = make(map[string]bool)
+	m.rebuildTablesList()
+}
+
+// toggleGroup collapses or expands the group under the cursor of the tables list, all of them when all is set
+func (m *model) toggleGroup(all bool) {
+	item, ok := m.vms.tablesList.SelectedItem().(types.TablesListItem)
+	if !ok {
+		return
+	}
+	if all {
+		groups, _ := m.tableGroups()
+		collapse := !lo.EveryBy(groups, func(g tableGroup) bool { return m.state.collapsedGroups[g.name] })
+		for _, g := range groups {
+			m.state.collapsedGroups[g.name] = collapse
+		}
+	} else if item.IsGroup() {
+		m.state.collapsedGroups[item.Group] = !m.state.collapsedGroups[item.Group]
+	} else {
+		return
+	}
+	idx := m.vms.tablesList.Index()
+	m.refreshTablesList()
+	if item.IsGroup() {
+		// the cursor stays on the toggled group
+		_, idx, _ = lo.FindIndexOf(m.vms.tablesList.Items(), func(i list.Item) bool { return i.(types.TablesListItem).Group == item.Group })
+	} else if selected := m.tableItemIndex(m.state.selectedSchema, m.state.selectedTable); selected >= 0 {
+		idx = selected
+	}
+	m.vms.tablesList.Select(lo.Clamp(idx, 0, len(m.vms.tablesList.Items())-1))
+}
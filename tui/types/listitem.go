@@ -1,19 +1,91 @@
 package types
 
-import "github.com/charmbracelet/bubbles/list"
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/samber/lo"
+)
 
-type TablesListItem string
+type TablesListItem struct {
+	Name   string
+	Marker string
+	Schema string
+	// Group is set on the headers of groups of tables, which have no Name
+	Group     string
+	Tables    int
+	Collapsed bool
+	// Grouped indents tables within a group
+	Grouped      bool
+	Columns      int
+	Indexes      int
+	ForeignKeys  int
+	NoPrimaryKey bool
+	Comment      string
+	// Warnings counts the lint diagnostics of the table
+	Warnings int
+}
+
+var _ list.DefaultItem = TablesListItem{}
 
-var _ list.DefaultItem = TablesListItem("")
+func (t TablesListItem) IsGroup() bool {
+	return t.Group != ""
+}
 
 func (t TablesListItem) FilterValue() string {
-	return string(t)
+	return t.Name
 }
 
 func (t TablesListItem) Title() string {
-	return string(t)
+	if t.IsGroup() {
+		return fmt.Sprintf("%s %s (%d)", lo.Ternary(t.Collapsed, "▸", "▾"), t.Group, t.Tables)
+	}
+	title := lo.Ternary(t.Grouped, "  ", "") + t.Marker + t.Name
+	if t.NoPrimaryKey {
+		title += " 🔓"
+	}
+	if t.Comment != "" {
+		title += " 💬"
+	}
+	if t.Warnings > 0 {
+		title += fmt.Sprintf(" ⚠%d", t.Warnings)
+	}
+	return title
 }
 
 func (t TablesListItem) Description() string {
-	return ""
+	if t.IsGroup() {
+		return fmt.Sprintf("%d tables", t.Tables)
+	}
+	description := fmt.Sprintf("%d cols · %d idx · %d fk", t.Columns, t.Indexes, t.ForeignKeys)
+	if t.Comment != "" {
+		description += " · " + t.Comment
+	}
+	return lo.Ternary(t.Grouped, "  ", "") + description
+}
+
+// TablesGrouping is how the tables list is grouped into a tree
+type TablesGrouping int
+
+const (
+	NoGrouping TablesGrouping = iota
+	PrefixGrouping
+	SchemaGrouping
+	RelationsGrouping
+)
+
+var TablesGroupings = []TablesGrouping{NoGrouping, PrefixGrouping, SchemaGrouping, RelationsGrouping}
+
+func (g TablesGrouping) Title() string {
+	switch g {
+	case NoGrouping:
+		return "tables"
+	case PrefixGrouping:
+		return "by name prefix"
+	case SchemaGrouping:
+		return "by schema"
+	case RelationsGrouping:
+		return "by relations"
+	default:
+		panic("unknown tables grouping")
+	}
 }
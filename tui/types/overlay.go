@@ -0,0 +1,23 @@
+package types
+
+// Overlay is a panel temporarily shown in place of the table details
+type Overlay int
+
+const (
+	NoOverlay Overlay = iota
+	HistoryOverlay
+	EnvsOverlay
+	CompareOverlay
+	MigrationOverlay
+	ApplyPlanOverlay
+	MigrationStatusOverlay
+	LintOverlay
+	StatsOverlay
+	QueryOverlay
+	ProfileOverlay
+	ColumnOverlay
+	CoverageOverlay
+	RedundantIndexesOverlay
+	GraphOverlay
+	ImpactOverlay
+)
@@ -6,8 +6,12 @@ const (
 	ColumnsTable TableDetailsSection = iota
 	IndexesTable
 	ForeignKeysTable
+	DataTable
 )
 
+// SchemaSections are the sections describing the structure of a table, available in every mode
+var SchemaSections = []TableDetailsSection{ColumnsTable, IndexesTable, ForeignKeysTable}
+
 func (section TableDetailsSection) Title() string {
 	switch section {
 	case ColumnsTable:
@@ -16,6 +20,8 @@ func (section TableDetailsSection) Title() string {
 		return "Indexes"
 	case ForeignKeysTable:
 		return "Foreign Keys"
+	case DataTable:
+		return "Data"
 	default:
 		panic("unknown table details section")
 	}
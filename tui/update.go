@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -24,18 +25,115 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case spinner.TickMsg:
 		m.vms.globe, cmd = m.vms.globe.Update(msg)
+	case dataLoadedMsg:
+		m.onDataLoaded(tmsg)
+		if m.state.reloadPending {
+			m.state.reloadPending = false
+			cmd = m.startLoad()
+		} else if tmsg.err == nil {
+			cmd = tea.Batch(m.startStats(), m.refreshDataPage())
+		}
+	case fileChangedMsg:
+		if m.state.loading {
+			m.state.reloadPending = true
+		} else {
+			cmd = m.startLoad()
+		}
+	case compareLoadedMsg:
+		m.onCompareLoaded(tmsg)
+	case applyPlanLoadedMsg:
+		m.onApplyPlanLoaded(tmsg)
+	case migrationStatusLoadedMsg:
+		m.onMigrationStatusLoaded(tmsg)
+	case lintLoadedMsg:
+		m.onLintLoaded(tmsg)
+	case statsLoadedMsg:
+		m.onStatsLoaded(tmsg)
+	case dataPageLoadedMsg:
+		m.onDataPageLoaded(tmsg)
+	case queryDoneMsg:
+		m.onQueryDone(tmsg)
+	case profileLoadedMsg:
+		m.onProfileLoaded(tmsg)
+	case columnDefaultLoadedMsg:
+		m.onColumnDefaultLoaded(tmsg)
+	case planLoadedMsg:
+		m.onPlanLoaded(tmsg)
+	case migrationWrittenMsg:
+		m.onMigrationWritten(tmsg)
+	case watchErrMsg:
+		m.state.bannerErr = fmt.Errorf("file watch failed: %w", tmsg.err)
 	case tea.KeyMsg:
+		m.state.bannerErr = nil
+		if m.state.overlay != types.NoOverlay {
+			cmd = m.updateOverlay(tmsg)
+			break
+		}
+		if m.state.filtering {
+			cmd = m.updateChartFilter(tmsg)
+			break
+		}
 		switch {
+		case m.state.loading && key.Matches(tmsg, keymap.Cancel):
+			m.cancelLoad()
+		case !m.state.loading && key.Matches(tmsg, keymap.Refresh):
+			cmd = m.startLoad()
+		case m.compareSource != nil && key.Matches(tmsg, keymap.Compare):
+			cmd = m.startSourceCompare()
+		case key.Matches(tmsg, keymap.Envs), key.Matches(tmsg, keymap.Compare):
+			m.openEnvs()
+		case !m.loaded() && !key.Matches(tmsg, keymap.Help, keymap.Quit):
+			// nothing to navigate yet
+		case key.Matches(tmsg, keymap.History):
+			m.openHistory()
+		case key.Matches(tmsg, keymap.ApplyPlan):
+			m.openApplyPlan()
+		case key.Matches(tmsg, keymap.Migrations):
+			cmd = m.openMigrationDir()
+		case key.Matches(tmsg, keymap.Lint):
+			cmd = m.openLint()
+		case key.Matches(tmsg, keymap.Stats):
+			m.openStats()
+		case key.Matches(tmsg, keymap.Query):
+			cmd = m.openQuery()
+		case key.Matches(tmsg, keymap.Profile):
+			cmd = m.openProfile()
+		case key.Matches(tmsg, keymap.Coverage):
+			m.openCoverage()
+		case key.Matches(tmsg, keymap.RedundantIndexes):
+			m.openRedundantIndexes()
+		case key.Matches(tmsg, keymap.Graph):
+			m.openGraph()
+		case key.Matches(tmsg, keymap.Impact):
+			m.openImpact()
+		case m.state.focused != types.TablesListFocused && m.state.selectedTab != types.DataTable && key.Matches(tmsg, keymap.Search):
+			cmd = m.startChartFilter()
+		case m.state.focused != types.TablesListFocused && m.state.selectedTab != types.DataTable && key.Matches(tmsg, keymap.Sort):
+			m.cycleChartSort()
+		case m.state.selectedTab == types.DataTable && key.Matches(tmsg, keymap.NextPage, keymap.PrevPage):
+			cmd = m.nextDataPage(key.Matches(tmsg, keymap.NextPage))
+		case m.state.focused == types.DetailsContentsFocused && m.state.selectedTab == types.ColumnsTable && key.Matches(tmsg, keymap.Select):
+			cmd = m.openColumnDetails()
+		case key.Matches(tmsg, keymap.Group):
+			m.cycleGrouping()
+		case key.Matches(tmsg, keymap.Describe):
+			m.toggleListDescriptions()
+		case m.state.focused == types.TablesListFocused && key.Matches(tmsg, keymap.Select, keymap.Toggle, keymap.ToggleAll):
+			m.toggleGroup(key.Matches(tmsg, keymap.ToggleAll))
 		case key.Matches(tmsg, keymap.Tab):
 			m.state.focused = (m.state.focused + 1) % 3
 		case key.Matches(tmsg, keymap.Left), key.Matches(tmsg, keymap.Right), key.Matches(tmsg, keymap.Up), key.Matches(tmsg, keymap.Down):
 			switch m.state.focused {
 			case types.TablesListFocused:
 				m.vms.tablesList, cmd = m.vms.tablesList.Update(msg)
-				m.onTableSelected(tableKey{m.state.selectedSchema, m.vms.tablesList.SelectedItem().FilterValue()})
+				if item, ok := m.vms.tablesList.SelectedItem().(types.TablesListItem); ok && !item.IsGroup() {
+					m.state.selectedSchema = item.Schema
+					m.onTableSelected(tableKey{item.Schema, item.Name})
+				}
 			case types.DetailsTabFocused:
 				if key.Matches(tmsg, keymap.Left) || key.Matches(tmsg, keymap.Right) {
-					m.state.selectedTab = (m.state.selectedTab + 1) % 3
+					m.state.selectedTab = (m.state.selectedTab + 1) % types.TableDetailsSection(len(m.sections()))
+					cmd = m.refreshDataPage()
 				}
 			case types.DetailsContentsFocused:
 				switch m.state.selectedTab {
@@ -45,6 +143,10 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.vms.idxChart, cmd = m.vms.idxChart.Update(msg)
 				case types.ForeignKeysTable:
 					m.vms.fksChart, cmd = m.vms.fksChart.Update(msg)
+				case types.DataTable:
+					if m.tableData != nil {
+						m.tableData.chart, cmd = m.tableData.chart.Update(msg)
+					}
 				}
 			}
 		case key.Matches(tmsg, keymap.Help):
@@ -56,3 +158,40 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	return m, cmd
 }
+
+func (m *model) updateOverlay(msg tea.KeyMsg) tea.Cmd {
+	switch m.state.overlay {
+	case types.HistoryOverlay:
+		return m.updateHistory(msg)
+	case types.EnvsOverlay:
+		return m.updateEnvs(msg)
+	case types.CompareOverlay:
+		return m.updateCompare(msg)
+	case types.MigrationOverlay:
+		return m.updatePlan(msg)
+	case types.ApplyPlanOverlay:
+		return m.updateApplyPlan(msg)
+	case types.MigrationStatusOverlay:
+		return m.updateMigrationDir(msg)
+	case types.LintOverlay:
+		return m.updateLint(msg)
+	case types.StatsOverlay:
+		return m.updateStats(msg)
+	case types.QueryOverlay:
+		return m.updateQuery(msg)
+	case types.ProfileOverlay:
+		return m.updateProfile(msg)
+	case types.ColumnOverlay:
+		return m.updateLinkList(&m.columnDetails.links, msg)
+	case types.CoverageOverlay:
+		return m.updateLinkList(&m.coverage.missing, msg)
+	case types.RedundantIndexesOverlay:
+		return m.updateLinkList(&m.redundant.redundant, msg)
+	case types.GraphOverlay:
+		return m.updateLinkList(&m.graph.tables, msg)
+	case types.ImpactOverlay:
+		return m.updateLinkList(&m.impact.nodes, msg)
+	default:
+		return nil
+	}
+}
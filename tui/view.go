@@ -7,10 +7,12 @@ import (
 	chart "github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/reallyliri/atlastui/tui/format"
+	"github.com/reallyliri/atlastui/tui/keymap"
 	"github.com/reallyliri/atlastui/tui/styles"
 	"github.com/reallyliri/atlastui/tui/types"
 	"github.com/samber/lo"
 	"strings"
+	"time"
 )
 
 func (m *model) View() string {
@@ -20,12 +22,34 @@ func (m *model) View() string {
 
 	borderWidth, borderHeight := styles.BorderFocusedStyle.GetFrameSize()
 
-	title := titleView(m.config.title, m.state.selectedSchema, m.state.selectedTable, m.vms.globe)
+	title := titleView(m.config.title, m.state.env, m.state.selectedSchema, m.state.selectedTable, m.vms.globe)
+	if status := m.statusView(); status != "" {
+		title = lipgloss.JoinVertical(lipgloss.Left, title, status)
+	}
 	footer := m.vms.help.View(m.config.keymap)
 	centerHeight := m.state.termHeight - lipgloss.Height(title) - lipgloss.Height(footer) - 5
 
+	// borders are not accounted by GetFrameSize, and a full width line loses its last column
+	if m.state.overlay != types.NoOverlay {
+		return lipgloss.JoinVertical(
+			lipgloss.Top,
+			title,
+			m.overlayView(m.state.termWidth-3, centerHeight+3),
+			footer,
+		)
+	}
+
+	if !m.loaded() {
+		return lipgloss.JoinVertical(
+			lipgloss.Top,
+			title,
+			m.loadingView(m.state.termWidth-3, centerHeight+3),
+			footer,
+		)
+	}
+
 	var tablesList string
-	var tabsView string
+	var tabs string
 	var details string
 
 	if m.state.selectedSchema != "" {
@@ -34,7 +58,11 @@ func (m *model) View() string {
 
 		if m.state.selectedTable != "" {
 			detailsWidth := (m.state.termWidth*2)/3 - borderWidth
-			tabsView = m.tabsView(detailsWidth, m.state.focused == types.DetailsTabFocused)
+			summary := m.dataSummary()
+			if m.state.selectedTab != types.DataTable {
+				summary = strings.Join(lo.Compact([]string{m.selectedTableStats(), m.chartViewSummary()}), format.TabsSeparator)
+			}
+			tabs = tabsView(m.sections(), m.state.selectedTab, detailsWidth, m.state.focused == types.DetailsTabFocused, summary)
 
 			var currChart chart.Model
 			switch m.state.selectedTab {
@@ -44,11 +72,23 @@ func (m *model) View() string {
 				currChart = m.vms.idxChart
 			case types.ForeignKeysTable:
 				currChart = m.vms.fksChart
+			case types.DataTable:
+				if m.tableData != nil {
+					currChart = m.tableData.chart
+				}
 			}
 			currChart.SetWidth(detailsWidth)
-			currChart.SetHeight(centerHeight - lipgloss.Height(tabsView) - borderHeight + 2)
+			currChart.SetHeight(centerHeight - lipgloss.Height(tabs) - borderHeight + 2)
 			if len(currChart.Rows()) == 0 {
 				noData := fmt.Sprintf("No %s", m.state.selectedTab.Title())
+				if d := m.tableData; m.state.selectedTab == types.DataTable && d != nil {
+					switch {
+					case d.err != nil:
+						noData = styles.ErrorStyle.Render(d.err.Error())
+					case d.page == nil:
+						noData = "Fetching rows..."
+					}
+				}
 				details = withBorder(styles.NoDataStyle.Copy().
 					Width(currChart.Width()).
 					Height(currChart.Height()+1).
@@ -67,24 +107,26 @@ func (m *model) View() string {
 			tablesList,
 			lipgloss.JoinVertical(
 				lipgloss.Top,
-				tabsView, details,
+				tabs, details,
 			),
 		),
 		footer,
 	)
 }
 
-func (m *model) tabsView(width int, focused bool) string {
-	tabs := []string{
-		tabView(types.ColumnsTable.Title(), m.state.selectedTab == types.ColumnsTable),
-		tabView(types.IndexesTable.Title(), m.state.selectedTab == types.IndexesTable),
-		tabView(types.ForeignKeysTable.Title(), m.state.selectedTab == types.ForeignKeysTable),
-	}
+// tabsView is the header of the table details, with an optional summary line below the tabs
+func tabsView(sections []types.TableDetailsSection, selected types.TableDetailsSection, width int, focused bool, summary string) string {
+	tabs := lo.Map(sections, func(section types.TableDetailsSection, _ int) string {
+		return tabView(section.Title(), selected == section)
+	})
 
 	row := lipgloss.NewStyle().
 		Width(width).
 		Align(lipgloss.Center).
 		Render(strings.Join(tabs, styles.SubTitleStyle.Render(format.TabsSeparator)))
+	if summary != "" {
+		row = lipgloss.JoinVertical(lipgloss.Left, row, styles.SubTitleStyle.Copy().Width(width).Align(lipgloss.Center).Render(summary))
+	}
 	return withBorder(row, focused)
 }
 
@@ -92,11 +134,15 @@ func tabView(title string, selected bool) string {
 	return lo.Ternary(selected, styles.TitleStyle.Render(title), styles.SubTitleStyle.Render(title))
 }
 
-func titleView(title, selectedSchema, selectedTable string, globe spinner.Model) string {
-	parts := make([]string, 0, 8)
+func titleView(title, env, selectedSchema, selectedTable string, globe spinner.Model) string {
+	parts := make([]string, 0, 10)
 	parts = append(parts, styles.TitleStyle.Render(title))
 	parts = append(parts, " ")
 	parts = append(parts, globe.View())
+	if env != "" {
+		parts = append(parts, styles.BreadcrumbsSectionStyle.Render(" ", format.BreadcrumbsSeparator, " env "))
+		parts = append(parts, styles.BreadcrumbsTitleStyle.Render(env))
+	}
 	if selectedSchema != "" {
 		parts = append(parts, styles.BreadcrumbsSectionStyle.Render(" ", format.BreadcrumbsSeparator, " schema "))
 		parts = append(parts, styles.BreadcrumbsTitleStyle.Render(selectedSchema))
@@ -116,3 +162,66 @@ func withBorder(ui string, focused bool) string {
 		return styles.BorderBluredStyle.Render(ui)
 	}
 }
+
+func (m *model) overlayView(width, height int) string {
+	switch m.state.overlay {
+	case types.HistoryOverlay:
+		return m.historyView(width, height)
+	case types.EnvsOverlay:
+		return m.envsView(width, height)
+	case types.CompareOverlay:
+		return m.compareView(width, height)
+	case types.MigrationOverlay:
+		return m.planView(width, height)
+	case types.ApplyPlanOverlay:
+		return m.applyPlanView(width, height)
+	case types.MigrationStatusOverlay:
+		return m.migrationDirView(width, height)
+	case types.LintOverlay:
+		return m.lintView(width, height)
+	case types.StatsOverlay:
+		return m.statsView(width, height)
+	case types.QueryOverlay:
+		return m.queryView(width, height)
+	case types.ProfileOverlay:
+		return m.profileView(width, height)
+	case types.ColumnOverlay:
+		return m.columnDetailsView(width, height)
+	case types.CoverageOverlay:
+		return m.coverageView(width, height)
+	case types.RedundantIndexesOverlay:
+		return m.redundantIndexesView(width, height)
+	case types.GraphOverlay:
+		return m.graphView(width, height)
+	case types.ImpactOverlay:
+		return m.impactView(width, height)
+	default:
+		return ""
+	}
+}
+
+func (m *model) loadingView(width, height int) string {
+	lines := make([]string, 0, maxStderrLines+3)
+	if m.state.loadErr != nil {
+		lines = append(lines, styles.ErrorStyle.Render("Failed to load schema"), "", styles.ErrorStyle.Render(m.state.loadErr.Error()))
+	} else {
+		lines = append(lines, fmt.Sprintf("%s Loading schema... %s", m.vms.globe.View(), formatElapsed(time.Since(m.state.loadStartedAt))))
+		lines = append(lines, styles.SubTitleStyle.Render(fmt.Sprintf("press %s to cancel", keymap.Cancel.Help().Key)))
+		// on failure the error already holds the cli stderr
+		if stderr := m.stderr.Lines(); len(stderr) > 0 {
+			lines = append(lines, "")
+			lines = append(lines, lo.Map(stderr, func(line string, _ int) string {
+				return styles.SubTitleStyle.Render(line)
+			})...)
+		}
+	}
+	return withBorder(centeredBox(width, height, lines...), true)
+}
+
+func centeredBox(width, height int, lines ...string) string {
+	return lipgloss.NewStyle().
+		Width(width).
+		Height(height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(lipgloss.JoinVertical(lipgloss.Center, lines...))
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+	"path/filepath"
+	"time"
+)
+
+// editors tend to save in several writes (or write and rename), reload once things settle
+const watchDebounce = 200 * time.Millisecond
+
+type fileChangedMsg struct{}
+
+type watchErrMsg struct {
+	err error
+}
+
+// WithWatch reloads whenever the file at the given path changes
+func WithWatch(fpath string) Option {
+	return func(c *runConfig) {
+		c.watchPath = fpath
+	}
+}
+
+// watch forwards changes of the file to the program until done is closed.
+// The parent directory is watched rather than the file itself, to survive editors replacing the file.
+func watch(prog *tea.Program, fpath string, done <-chan struct{}) error {
+	fpath, err := filepath.Abs(fpath)
+	if err != nil {
+		return err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	if err = watcher.Add(filepath.Dir(fpath)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch '%s': %w", fpath, err)
+	}
+
+	go func() {
+		defer watcher.Close()
+		debounce := time.NewTimer(watchDebounce)
+		debounce.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == fpath && !event.Has(fsnotify.Chmod) {
+					debounce.Reset(watchDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				prog.Send(watchErrMsg{err: err})
+			case <-debounce.C:
+				prog.Send(fileChangedMsg{})
+			}
+		}
+	}()
+	return nil
+}